@@ -18,6 +18,9 @@ import (
 	api "github.com/johnsonabraham/moneycontrolscraper/internal/moneycontrol/api"
 	repository "github.com/johnsonabraham/moneycontrolscraper/internal/moneycontrol/repository"
 	service "github.com/johnsonabraham/moneycontrolscraper/internal/moneycontrol/service"
+	mfapi "github.com/johnsonabraham/moneycontrolscraper/internal/mutualfund/api"
+	mfrepository "github.com/johnsonabraham/moneycontrolscraper/internal/mutualfund/repository"
+	mfservice "github.com/johnsonabraham/moneycontrolscraper/internal/mutualfund/service"
 	P "github.com/johnsonabraham/moneycontrolscraper/internal/persist"
 )
 
@@ -75,16 +78,66 @@ func main() {
 	db := P.ConnectDB(cfg)
 
 	moneyControlRepository := repository.NewMoneycontrolRepository(db, mlog, cfg)
-	moneyControlService := service.NewMoneyControlService(mlog, cfg, moneyControlRepository)
+	sink, err := service.NewSink(mlog, cfg)
+	if err != nil {
+		app.Logger().Fatalf("%s: due to :%s", errStartingMoneybsMS, err)
+	}
+	calendar, err := service.NewMarketCalendar(cfg.MarketHolidays)
+	if err != nil {
+		app.Logger().Fatalf("%s: due to :%s", errStartingMoneybsMS, err)
+	}
+	jobRunner := service.NewJobRunner(mlog, cfg, repository.NewJobRepository(db, mlog))
+	moneyControlService := service.NewMoneyControlService(mlog, cfg, moneyControlRepository, sink, jobRunner, calendar)
+	go moneyControlService.ResumeBackfill()
+	if err := moneyControlService.StartScheduler(); err != nil {
+		app.Logger().Fatalf("%s: due to :%s", errStartingMoneybsMS, err)
+	}
 	moneyControlHandler := api.NewMoneyControlHandler(moneyControlService, mlog, cfg)
+	mutualFundService := mfservice.NewMutualFundService(mlog, cfg, mfrepository.NewMutualFundRepository(db, mlog), jobRunner)
+	mutualFundHandler := mfapi.NewMutualFundHandler(mutualFundService, mlog, cfg)
 
 	apiv1 := app.Party("/api/v1")
 	apiv1.Get("/auth", auth.GenerateToken(signer, cfg))
 	apiv1.Use(verifyMiddleware)
 
+	apiv1.Get("/companies", moneyControlHandler.ListCompanies)
+	apiv1.Get("/companies/{nseid}", moneyControlHandler.GetCompany)
+	apiv1.Get("/companies/{nseid}/peers", moneyControlHandler.GetPeers)
+	apiv1.Get("/sectors", moneyControlHandler.GetSectorSummaries)
+	apiv1.Get("/indices", moneyControlHandler.ListIndices)
+	apiv1.Get("/indices/{id:int64}/constituents", moneyControlHandler.GetIndexConstituents)
+	apiv1.Get("/collectIndices", moneyControlHandler.CollectIndices)
+	apiv1.Get("/jobs", moneyControlHandler.ListJobs)
+	apiv1.Get("/jobs/{id:int64}", moneyControlHandler.GetJob)
+	apiv1.Get("/schedules", moneyControlHandler.GetSchedules)
 	apiv1.Get("/collectCompanySymbols", moneyControlHandler.CollectMoneycontrolSymbols)
+	apiv1.Get("/enrichCompanies", moneyControlHandler.EnrichCompanies)
 	apiv1.Get("/collectDividendHistory", moneyControlHandler.CollectDividendData)
+	apiv1.Get("/collectDividendHistoryBulk", moneyControlHandler.CollectDividendDataBulk)
+	apiv1.Get("/dividendHistory", moneyControlHandler.GetDividendHistory)
+	apiv1.Get("/collectCorporateActions", moneyControlHandler.CollectCorporateActions)
+	apiv1.Get("/corporateActions", moneyControlHandler.GetCorporateActions)
 	apiv1.Get("/collectHistoricalDailyData", moneyControlHandler.CollectHistoricalDailyDate)
+	apiv1.Get("/backfillHistoricalData", moneyControlHandler.StartHistoricalBackfill)
+	apiv1.Get("/backfillHistoricalData/status", moneyControlHandler.GetHistoricalBackfillStatus)
+	apiv1.Get("/historical", moneyControlHandler.GetHistoricalData)
+	apiv1.Get("/collectAnnouncements", moneyControlHandler.CollectAnnouncements)
+	apiv1.Get("/announcements", moneyControlHandler.GetAnnouncements)
+	apiv1.Get("/collectShareholding", moneyControlHandler.CollectShareholding)
+	apiv1.Get("/shareholding", moneyControlHandler.GetShareholding)
+	apiv1.Get("/collectFinancials", moneyControlHandler.CollectFinancials)
+	apiv1.Get("/financials", moneyControlHandler.GetFinancials)
+	apiv1.Get("/collectMarketDepth", moneyControlHandler.CollectMarketDepth)
+	apiv1.Get("/marketDepth", moneyControlHandler.GetMarketDepth)
+	apiv1.Get("/price", moneyControlHandler.GetStockPrice)
+	apiv1.Get("/technicals", moneyControlHandler.GetStockTechnicals)
+	apiv1.Get("/movingAverages", moneyControlHandler.GetStockMovingAverages)
+	apiv1.Get("/pivotLevels", moneyControlHandler.GetStockPivotLevels)
+	apiv1.Get("/technicalSnapshot", moneyControlHandler.GetStockTechnicalSnapshot)
+	apiv1.Get("/mutualFunds", mutualFundHandler.ListSchemes)
+	apiv1.Get("/mutualFunds/{code}/nav", mutualFundHandler.GetNAVHistory)
+	apiv1.Get("/collectMutualFundSchemes", mutualFundHandler.CollectSchemes)
+	apiv1.Get("/collectMutualFundNAV", mutualFundHandler.CollectNAVHistory)
 	port := ":" + cfg.AppPort
 	if err := app.Listen(port, iris.WithOptimizations); err != nil {
 		app.Logger().Fatalf("%s: due to :%s", errStartingMoneybsMS, err)
@@ -2,6 +2,7 @@ package config
 
 import (
 	"errors"
+	"time"
 
 	"github.com/caarlos0/env/v7"
 	"github.com/kataras/golog"
@@ -11,22 +12,46 @@ import (
 var errLoadingEnvVar = errors.New("failed to load the env vars")
 
 type AppEnvVars struct {
-	PGHostIP                              string `env:"PG_HOST"`
-	PGUser                                string `env:"PG_USER"`
-	PGPassword                            string `env:"PG_PASSWORD"`
-	PGDbName                              string `env:"PG_DBNAME"`
-	MsEnv                                 string `env:"MS_ENV"`
-	APIKey                                string `env:"API_KEY"`
-	MoneyControlSymbolURL                 string `env:"MONEYCONTROL_SYMBOL_URL"`
-	MoneyControlDividendURL               string `env:"MONEYCONTROL_DIVIDEND_URL"`
-	MoneyControlCompDetailsUrl            string `env:"MONEYCONTROL_COMP_DETAILS_URL"`
-	MoneyControlHistoricalDataUrl         string `env:"MONEYCONTROL_HISTORICAL_DATA_URL"`
-	MoneyBSAPIKey                         string `env:"MONEYBS_API_KEY"`
-	MoneyBSBaseURL                        string `env:"MONEYBS_BASE_URL"`
-	MoneyBSAuthEndpoint                   string `env:"MONEYBS_AUTH_ENDPOINT"`
-	MoneyBSHistoricalDataEndpoint         string `env:"MONEYBS_HISTORICAL_DATA_ENDPOINT"`
-	MoneyBSHistoricalDividendDataEndpoint string `env:"MONEYBS_HISTORICAL_DIVIDEND_DATA_ENDPOINT"`
-	AppPort                               string `env:"APP_PORT"`
+	PGHostIP                              string        `env:"PG_HOST"`
+	PGUser                                string        `env:"PG_USER"`
+	PGPassword                            string        `env:"PG_PASSWORD"`
+	PGDbName                              string        `env:"PG_DBNAME"`
+	MsEnv                                 string        `env:"MS_ENV"`
+	APIKey                                string        `env:"API_KEY"`
+	MoneyControlSymbolURL                 string        `env:"MONEYCONTROL_SYMBOL_URL"`
+	MoneyControlDividendURL               string        `env:"MONEYCONTROL_DIVIDEND_URL"`
+	MoneyControlCompDetailsUrl            string        `env:"MONEYCONTROL_COMP_DETAILS_URL"`
+	MoneyControlHistoricalDataUrl         string        `env:"MONEYCONTROL_HISTORICAL_DATA_URL"`
+	MoneyControlBSEHistoricalDataUrl      string        `env:"MONEYCONTROL_BSE_HISTORICAL_DATA_URL" envDefault:""`
+	MoneyControlCorporateActionsURL       string        `env:"MONEYCONTROL_CORPORATE_ACTIONS_URL" envDefault:"https://www.moneycontrol.com/company-facts/%s/%s/%s"`
+	MoneyControlShareholdingURL           string        `env:"MONEYCONTROL_SHAREHOLDING_URL" envDefault:"https://www.moneycontrol.com/company-facts/%s/shareholding-pattern/%s"`
+	MoneyControlFinancialsURL             string        `env:"MONEYCONTROL_FINANCIALS_URL" envDefault:"https://www.moneycontrol.com/financials/%s/%s/%s"`
+	MoneyControlAnnouncementsURL          string        `env:"MONEYCONTROL_ANNOUNCEMENTS_URL" envDefault:"https://www.moneycontrol.com/company-notices/%s/notices/%s"`
+	MoneyControlIndexLevelURL             string        `env:"MONEYCONTROL_INDEX_LEVEL_URL" envDefault:"https://priceapi.moneycontrol.com/pricefeed/notapplicable/inidicesindia/%s"`
+	MoneyControlIndexConstituentsURL      string        `env:"MONEYCONTROL_INDEX_CONSTITUENTS_URL" envDefault:"https://www.moneycontrol.com/markets/indian-indices/%s"`
+	Indices                               string        `env:"INDICES" envDefault:""`
+	MoneyControlMFSchemesURL              string        `env:"MONEYCONTROL_MF_SCHEMES_URL" envDefault:""`
+	MoneyControlMFNAVHistoryURL           string        `env:"MONEYCONTROL_MF_NAV_HISTORY_URL" envDefault:""`
+	Sink                                  string        `env:"SINK" envDefault:""`
+	MoneyBSAPIKey                         string        `env:"MONEYBS_API_KEY" envDefault:""`
+	MoneyBSBaseURL                        string        `env:"MONEYBS_BASE_URL" envDefault:""`
+	MoneyBSAuthEndpoint                   string        `env:"MONEYBS_AUTH_ENDPOINT" envDefault:""`
+	MoneyBSHistoricalDataEndpoint         string        `env:"MONEYBS_HISTORICAL_DATA_ENDPOINT" envDefault:""`
+	MoneyBSHistoricalDividendDataEndpoint string        `env:"MONEYBS_HISTORICAL_DIVIDEND_DATA_ENDPOINT" envDefault:""`
+	MoneyBSCompanyInfoEndpoint            string        `env:"MONEYBS_COMPANY_INFO_ENDPOINT" envDefault:""`
+	AppPort                               string        `env:"APP_PORT"`
+	BulkWorkers                           int           `env:"BULK_WORKERS" envDefault:"4"`
+	BulkRequestInterval                   time.Duration `env:"BULK_REQUEST_INTERVAL" envDefault:"1s"`
+	EnrichmentMaxAge                      time.Duration `env:"ENRICHMENT_MAX_AGE" envDefault:"168h"`
+	EnrichmentRequestInterval             time.Duration `env:"ENRICHMENT_REQUEST_INTERVAL" envDefault:"5s"`
+	JobWorkers                            int           `env:"JOB_WORKERS" envDefault:"2"`
+	LongJobWorkers                        int           `env:"LONG_JOB_WORKERS" envDefault:"1"`
+	ScheduleSymbols                       string        `env:"SCHEDULE_SYMBOLS" envDefault:""`
+	ScheduleDividends                     string        `env:"SCHEDULE_DIVIDENDS" envDefault:""`
+	ScheduleHistorical                    string        `env:"SCHEDULE_HISTORICAL" envDefault:""`
+	MarketHolidays                        string        `env:"MARKET_HOLIDAYS" envDefault:""`
+	BackfillWorkers                       int           `env:"BACKFILL_WORKERS" envDefault:"2"`
+	BackfillRequestInterval               time.Duration `env:"BACKFILL_REQUEST_INTERVAL" envDefault:"2s"`
 }
 
 func LoadEnvVars(vlog *golog.Logger) *AppEnvVars {
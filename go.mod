@@ -2,7 +2,10 @@ module github.com/johnsonabraham/moneycontrolscraper
 
 go 1.20
 
-require github.com/kataras/iris/v12 v12.2.0
+require (
+	github.com/kataras/iris/v12 v12.2.0
+	gorm.io/gorm v1.25.0
+)
 
 require (
 	github.com/KyleBanks/depth v1.2.1 // indirect
@@ -27,7 +30,6 @@ require (
 	github.com/swaggo/swag v1.8.10 // indirect
 	golang.org/x/tools v0.6.0 // indirect
 	gopkg.in/yaml.v2 v2.4.0 // indirect
-	gorm.io/gorm v1.25.0 // indirect
 )
 
 require (
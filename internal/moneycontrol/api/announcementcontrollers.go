@@ -0,0 +1,77 @@
+package moneycontrolapi
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/johnsonabraham/moneycontrolscraper/internal/moneycontrol/models"
+	"github.com/kataras/iris/v12"
+	"gorm.io/gorm"
+)
+
+// CollectAnnouncements queues a scrape of the corporate announcements of company
+func (h *MoneyControlHandler) CollectAnnouncements(ctx iris.Context) {
+	company := ctx.URLParam("company")
+
+	h.mlog.Info(fmt.Sprintf("Moneycontrol announcements collection requested for %s", company))
+	job, err := h.moneyControlService.EnqueueAnnouncementCollection(company)
+	if err != nil {
+		h.announcementsFailed(ctx, company, err)
+		return
+	}
+	h.mlog.Info(fmt.Sprintf("Announcements collection for company %s queued as job %d", company, job.ID))
+
+	ctx.StatusCode(iris.StatusAccepted)
+	ctx.JSON(job)
+}
+
+// GetAnnouncements returns stored announcements of company, or of every company without it,
+// dated between the optional from and to dates. Polling clients pass the next_since of the previous
+// response as since to get only announcements stored after it, at most limit at a time. Announcements
+// show up a minute after they are scraped.
+func (h *MoneyControlHandler) GetAnnouncements(ctx iris.Context) {
+	company := ctx.URLParam("company")
+	from, to, ok := h.dateRange(ctx)
+	if !ok {
+		return
+	}
+	since, err := ctx.URLParamInt64("since")
+	if err != nil && ctx.URLParamExists("since") {
+		ctx.StopWithJSON(iris.StatusBadRequest, models.FailedResponse{
+			Status:   iris.StatusBadRequest,
+			ErrorMsg: "since must be a number",
+		})
+		return
+	}
+
+	page, err := h.moneyControlService.GetAnnouncements(company, models.AnnouncementFilter{
+		From:  from,
+		To:    to,
+		Since: since,
+		Limit: ctx.URLParamIntDefault("limit", 0),
+	})
+	if err != nil {
+		h.announcementsFailed(ctx, company, err)
+		return
+	}
+	ctx.StatusCode(iris.StatusOK)
+	ctx.JSON(page)
+}
+
+func (h *MoneyControlHandler) announcementsFailed(ctx iris.Context, company string, err error) {
+	if h.ambiguousCompany(ctx, err) {
+		return
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		ctx.StopWithJSON(iris.StatusNotFound, models.FailedResponse{
+			Status:   iris.StatusNotFound,
+			ErrorMsg: "Company not found",
+		})
+		return
+	}
+	h.mlog.Error(fmt.Sprintf("Error handling announcements for %s", company), err)
+	ctx.StopWithJSON(iris.StatusInternalServerError, models.FailedResponse{
+		Status:   iris.StatusInternalServerError,
+		ErrorMsg: "Something went wrong, please try again after some time",
+	})
+}
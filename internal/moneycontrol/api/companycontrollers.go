@@ -0,0 +1,77 @@
+package moneycontrolapi
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/johnsonabraham/moneycontrolscraper/internal/moneycontrol/models"
+	service "github.com/johnsonabraham/moneycontrolscraper/internal/moneycontrol/service"
+	"github.com/kataras/iris/v12"
+	"gorm.io/gorm"
+)
+
+// ListCompanies returns a page of the company directory. q searches names, exchange ids and ISINs,
+// sector, main_sector, sub_sector, index, min_pe and max_pe filter, sort is name, market_cap_desc,
+// market_cap_asc, pe_asc or pe_desc.
+func (h *MoneyControlHandler) ListCompanies(ctx iris.Context) {
+	filter := models.CompanyFilter{
+		Query:      ctx.URLParam("q"),
+		Sector:     ctx.URLParam("sector"),
+		MainSector: ctx.URLParam("main_sector"),
+		SubSector:  ctx.URLParam("sub_sector"),
+		Index:      ctx.URLParam("index"),
+		Sort:       ctx.URLParam("sort"),
+		Page:       ctx.URLParamIntDefault("page", 1),
+		PageSize:   ctx.URLParamIntDefault("page_size", 0),
+	}
+	filter.MinPE, _ = ctx.URLParamFloat64("min_pe")
+	filter.MaxPE, _ = ctx.URLParamFloat64("max_pe")
+	filter.IncludeDelisted, _ = ctx.URLParamBool("include_delisted")
+
+	page, err := h.moneyControlService.SearchCompanies(filter)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidSort) {
+			ctx.StopWithJSON(iris.StatusBadRequest, models.FailedResponse{
+				Status:   iris.StatusBadRequest,
+				ErrorMsg: "sort must be one of name, market_cap_desc, market_cap_asc, pe_asc or pe_desc",
+			})
+			return
+		}
+		h.mlog.Error("Error listing companies", err)
+		ctx.StopWithJSON(iris.StatusInternalServerError, models.FailedResponse{
+			Status:   iris.StatusInternalServerError,
+			ErrorMsg: "Something went wrong, please try again after some time",
+		})
+		return
+	}
+	ctx.StatusCode(iris.StatusOK)
+	ctx.JSON(page)
+}
+
+// GetCompany returns the company the nseid path parameter identifies, which may also be a BSE scrip
+// code, symbol, name or ISIN
+func (h *MoneyControlHandler) GetCompany(ctx iris.Context) {
+	nseID := ctx.Params().Get("nseid")
+
+	company, err := h.moneyControlService.GetCompany(nseID)
+	if err != nil {
+		if h.ambiguousCompany(ctx, err) {
+			return
+		}
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ctx.StopWithJSON(iris.StatusNotFound, models.FailedResponse{
+				Status:   iris.StatusNotFound,
+				ErrorMsg: "Company not found",
+			})
+			return
+		}
+		h.mlog.Error(fmt.Sprintf("Error reading company %s", nseID), err)
+		ctx.StopWithJSON(iris.StatusInternalServerError, models.FailedResponse{
+			Status:   iris.StatusInternalServerError,
+			ErrorMsg: "Something went wrong, please try again after some time",
+		})
+		return
+	}
+	ctx.StatusCode(iris.StatusOK)
+	ctx.JSON(company)
+}
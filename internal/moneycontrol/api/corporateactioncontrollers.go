@@ -0,0 +1,65 @@
+package moneycontrolapi
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/johnsonabraham/moneycontrolscraper/internal/moneycontrol/models"
+	service "github.com/johnsonabraham/moneycontrolscraper/internal/moneycontrol/service"
+	"github.com/kataras/iris/v12"
+	"gorm.io/gorm"
+)
+
+// CollectCorporateActions queues a scrape of the bonus issues, splits and rights issues of company
+func (h *MoneyControlHandler) CollectCorporateActions(ctx iris.Context) {
+	company := ctx.URLParam("company")
+
+	h.mlog.Info(fmt.Sprintf("Moneycontrol corporate actions collection requested for %s", company))
+	job, err := h.moneyControlService.EnqueueCorporateActionsCollection(company)
+	if err != nil {
+		h.corporateActionsFailed(ctx, company, err)
+		return
+	}
+	h.mlog.Info(fmt.Sprintf("Corporate actions collection for company %s queued as job %d", company, job.ID))
+
+	ctx.StatusCode(iris.StatusAccepted)
+	ctx.JSON(job)
+}
+
+// GetCorporateActions returns the stored corporate actions of company, optionally of one type
+// among bonus, split or rights
+func (h *MoneyControlHandler) GetCorporateActions(ctx iris.Context) {
+	company := ctx.URLParam("company")
+
+	actions, err := h.moneyControlService.GetCorporateActions(company, ctx.URLParam("type"))
+	if err != nil {
+		h.corporateActionsFailed(ctx, company, err)
+		return
+	}
+	ctx.StatusCode(iris.StatusOK)
+	ctx.JSON(actions)
+}
+
+func (h *MoneyControlHandler) corporateActionsFailed(ctx iris.Context, company string, err error) {
+	if h.ambiguousCompany(ctx, err) {
+		return
+	}
+	switch {
+	case errors.Is(err, service.ErrInvalidActionType):
+		ctx.StopWithJSON(iris.StatusBadRequest, models.FailedResponse{
+			Status:   iris.StatusBadRequest,
+			ErrorMsg: "type must be one of bonus, split or rights",
+		})
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		ctx.StopWithJSON(iris.StatusNotFound, models.FailedResponse{
+			Status:   iris.StatusNotFound,
+			ErrorMsg: "Company not found",
+		})
+	default:
+		h.mlog.Error(fmt.Sprintf("Error handling corporate actions for %s", company), err)
+		ctx.StopWithJSON(iris.StatusInternalServerError, models.FailedResponse{
+			Status:   iris.StatusInternalServerError,
+			ErrorMsg: "Something went wrong, please try again after some time",
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package moneycontrolapi
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/johnsonabraham/moneycontrolscraper/internal/moneycontrol/models"
+	service "github.com/johnsonabraham/moneycontrolscraper/internal/moneycontrol/service"
+	"github.com/kataras/iris/v12"
+	"gorm.io/gorm"
+)
+
+// CollectFinancials queues a scrape of the financial statements of company
+func (h *MoneyControlHandler) CollectFinancials(ctx iris.Context) {
+	company := ctx.URLParam("company")
+
+	h.mlog.Info(fmt.Sprintf("Moneycontrol financials collection requested for %s", company))
+	job, err := h.moneyControlService.EnqueueFinancialsCollection(company)
+	if err != nil {
+		h.financialsFailed(ctx, company, err)
+		return
+	}
+	h.mlog.Info(fmt.Sprintf("Financials collection for company %s queued as job %d", company, job.ID))
+
+	ctx.StatusCode(iris.StatusAccepted)
+	ctx.JSON(job)
+}
+
+// GetFinancials returns the stored financial statements of company. statement is one of
+// quarterly_results, profit_loss, balance_sheet or cash_flow and period a YYYY-MM month,
+// both optional.
+func (h *MoneyControlHandler) GetFinancials(ctx iris.Context) {
+	company := ctx.URLParam("company")
+
+	items, err := h.moneyControlService.GetFinancials(company, ctx.URLParam("statement"), ctx.URLParam("period"))
+	if err != nil {
+		h.financialsFailed(ctx, company, err)
+		return
+	}
+	ctx.StatusCode(iris.StatusOK)
+	ctx.JSON(items)
+}
+
+func (h *MoneyControlHandler) financialsFailed(ctx iris.Context, company string, err error) {
+	if h.ambiguousCompany(ctx, err) {
+		return
+	}
+	switch {
+	case errors.Is(err, service.ErrInvalidStatement):
+		ctx.StopWithJSON(iris.StatusBadRequest, models.FailedResponse{
+			Status:   iris.StatusBadRequest,
+			ErrorMsg: "statement must be one of quarterly_results, profit_loss, balance_sheet or cash_flow",
+		})
+	case errors.Is(err, service.ErrInvalidPeriod):
+		ctx.StopWithJSON(iris.StatusBadRequest, models.FailedResponse{
+			Status:   iris.StatusBadRequest,
+			ErrorMsg: "period must be a month in YYYY-MM format",
+		})
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		ctx.StopWithJSON(iris.StatusNotFound, models.FailedResponse{
+			Status:   iris.StatusNotFound,
+			ErrorMsg: "Company not found",
+		})
+	default:
+		h.mlog.Error(fmt.Sprintf("Error handling financials for %s", company), err)
+		ctx.StopWithJSON(iris.StatusInternalServerError, models.FailedResponse{
+			Status:   iris.StatusInternalServerError,
+			ErrorMsg: "Something went wrong, please try again after some time",
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package moneycontrolapi
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/johnsonabraham/moneycontrolscraper/internal/moneycontrol/models"
+	service "github.com/johnsonabraham/moneycontrolscraper/internal/moneycontrol/service"
+	"github.com/kataras/iris/v12"
+	"gorm.io/gorm"
+)
+
+// CollectIndices queues a refresh of the level and constituents of every configured index
+func (h *MoneyControlHandler) CollectIndices(ctx iris.Context) {
+	job, err := h.moneyControlService.EnqueueIndexCollection()
+	if err != nil {
+		if errors.Is(err, service.ErrNoIndices) {
+			ctx.StopWithJSON(iris.StatusBadRequest, models.FailedResponse{
+				Status:   iris.StatusBadRequest,
+				ErrorMsg: "No indices configured",
+			})
+			return
+		}
+		h.mlog.Error("Error queueing index collection", err)
+		ctx.StopWithJSON(iris.StatusInternalServerError, models.FailedResponse{
+			Status:   iris.StatusInternalServerError,
+			ErrorMsg: "Something went wrong, please try again after some time",
+		})
+		return
+	}
+	ctx.StatusCode(iris.StatusAccepted)
+	ctx.JSON(job)
+}
+
+func (h *MoneyControlHandler) ListIndices(ctx iris.Context) {
+	indices, err := h.moneyControlService.ListIndices()
+	if err != nil {
+		h.mlog.Error("Error listing indices", err)
+		ctx.StopWithJSON(iris.StatusInternalServerError, models.FailedResponse{
+			Status:   iris.StatusInternalServerError,
+			ErrorMsg: "Something went wrong, please try again after some time",
+		})
+		return
+	}
+	ctx.StatusCode(iris.StatusOK)
+	ctx.JSON(indices)
+}
+
+// GetIndexConstituents returns the companies of an index with their weights, heaviest first
+func (h *MoneyControlHandler) GetIndexConstituents(ctx iris.Context) {
+	id, err := ctx.Params().GetInt64("id")
+	if err != nil {
+		ctx.StopWithJSON(iris.StatusBadRequest, models.FailedResponse{
+			Status:   iris.StatusBadRequest,
+			ErrorMsg: "index id must be a number",
+		})
+		return
+	}
+
+	constituents, err := h.moneyControlService.GetIndexConstituents(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ctx.StopWithJSON(iris.StatusNotFound, models.FailedResponse{
+				Status:   iris.StatusNotFound,
+				ErrorMsg: "Index not found",
+			})
+			return
+		}
+		h.mlog.Error(fmt.Sprintf("Error reading constituents of index %d", id), err)
+		ctx.StopWithJSON(iris.StatusInternalServerError, models.FailedResponse{
+			Status:   iris.StatusInternalServerError,
+			ErrorMsg: "Something went wrong, please try again after some time",
+		})
+		return
+	}
+	ctx.StatusCode(iris.StatusOK)
+	ctx.JSON(constituents)
+}
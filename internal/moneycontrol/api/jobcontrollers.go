@@ -0,0 +1,79 @@
+package moneycontrolapi
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/johnsonabraham/moneycontrolscraper/internal/moneycontrol/models"
+	"github.com/kataras/iris/v12"
+	"gorm.io/gorm"
+)
+
+// defaultJobListLimit caps the job listing when no limit is given
+const defaultJobListLimit = 50
+
+func (h *MoneyControlHandler) GetJob(ctx iris.Context) {
+	id, err := ctx.Params().GetInt64("id")
+	if err != nil {
+		ctx.StopWithJSON(iris.StatusBadRequest, models.FailedResponse{
+			Status:   iris.StatusBadRequest,
+			ErrorMsg: "job id must be a number",
+		})
+		return
+	}
+
+	job, err := h.moneyControlService.GetJob(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ctx.StopWithJSON(iris.StatusNotFound, models.FailedResponse{
+				Status:   iris.StatusNotFound,
+				ErrorMsg: "Job not found",
+			})
+			return
+		}
+		h.mlog.Error(fmt.Sprintf("Error reading job %d", id), err)
+		ctx.StopWithJSON(iris.StatusInternalServerError, models.FailedResponse{
+			Status:   iris.StatusInternalServerError,
+			ErrorMsg: "Something went wrong, please try again after some time",
+		})
+		return
+	}
+	ctx.StatusCode(iris.StatusOK)
+	ctx.JSON(job)
+}
+
+// ListJobs returns the newest jobs, filtered by the optional type and state params
+func (h *MoneyControlHandler) ListJobs(ctx iris.Context) {
+	filter := models.JobFilter{
+		Type:  ctx.URLParam("type"),
+		State: ctx.URLParam("state"),
+		Limit: ctx.URLParamIntDefault("limit", defaultJobListLimit),
+	}
+
+	jobs, err := h.moneyControlService.ListJobs(filter)
+	if err != nil {
+		h.mlog.Error("Error listing jobs", err)
+		ctx.StopWithJSON(iris.StatusInternalServerError, models.FailedResponse{
+			Status:   iris.StatusInternalServerError,
+			ErrorMsg: "Something went wrong, please try again after some time",
+		})
+		return
+	}
+	ctx.StatusCode(iris.StatusOK)
+	ctx.JSON(jobs)
+}
+
+// GetSchedules returns the configured recurring scrapes with their next and last runs
+func (h *MoneyControlHandler) GetSchedules(ctx iris.Context) {
+	schedules, err := h.moneyControlService.GetSchedules()
+	if err != nil {
+		h.mlog.Error("Error reading schedules", err)
+		ctx.StopWithJSON(iris.StatusInternalServerError, models.FailedResponse{
+			Status:   iris.StatusInternalServerError,
+			ErrorMsg: "Something went wrong, please try again after some time",
+		})
+		return
+	}
+	ctx.StatusCode(iris.StatusOK)
+	ctx.JSON(schedules)
+}
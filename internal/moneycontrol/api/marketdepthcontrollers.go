@@ -0,0 +1,62 @@
+package moneycontrolapi
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/johnsonabraham/moneycontrolscraper/internal/moneycontrol/models"
+	"github.com/kataras/iris/v12"
+	"gorm.io/gorm"
+)
+
+// CollectMarketDepth queues a capture of the current best five bid and ask levels of company
+func (h *MoneyControlHandler) CollectMarketDepth(ctx iris.Context) {
+	company := ctx.URLParam("company")
+
+	h.mlog.Info(fmt.Sprintf("Moneycontrol market depth collection requested for %s", company))
+	job, err := h.moneyControlService.EnqueueMarketDepthCollection(company)
+	if err != nil {
+		h.marketDepthFailed(ctx, company, err)
+		return
+	}
+	h.mlog.Info(fmt.Sprintf("Market depth collection for company %s queued as job %d", company, job.ID))
+
+	ctx.StatusCode(iris.StatusAccepted)
+	ctx.JSON(job)
+}
+
+// GetMarketDepth returns the stored order book snapshots of company, newest first. from and to
+// are optional dates and limit caps the snapshots returned.
+func (h *MoneyControlHandler) GetMarketDepth(ctx iris.Context) {
+	company := ctx.URLParam("company")
+	from, to, ok := h.dateRange(ctx)
+	if !ok {
+		return
+	}
+
+	snapshots, err := h.moneyControlService.GetMarketDepth(company, from, to, ctx.URLParamIntDefault("limit", 0))
+	if err != nil {
+		h.marketDepthFailed(ctx, company, err)
+		return
+	}
+	ctx.StatusCode(iris.StatusOK)
+	ctx.JSON(snapshots)
+}
+
+func (h *MoneyControlHandler) marketDepthFailed(ctx iris.Context, company string, err error) {
+	if h.ambiguousCompany(ctx, err) {
+		return
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		ctx.StopWithJSON(iris.StatusNotFound, models.FailedResponse{
+			Status:   iris.StatusNotFound,
+			ErrorMsg: "Company not found",
+		})
+		return
+	}
+	h.mlog.Error(fmt.Sprintf("Error reading market depth for %s", company), err)
+	ctx.StopWithJSON(iris.StatusInternalServerError, models.FailedResponse{
+		Status:   iris.StatusInternalServerError,
+		ErrorMsg: "Something went wrong, please try again after some time",
+	})
+}
@@ -3,10 +3,13 @@ package moneycontrolapi
 import (
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/johnsonabraham/moneycontrolscraper/config"
 	"github.com/johnsonabraham/moneycontrolscraper/internal/moneycontrol/models"
 	service "github.com/johnsonabraham/moneycontrolscraper/internal/moneycontrol/service"
+	"github.com/johnsonabraham/moneycontrolscraper/pkg/params"
 	"github.com/kataras/golog"
 	"github.com/kataras/iris/v12"
 	"gorm.io/gorm"
@@ -27,8 +30,9 @@ func NewMoneyControlHandler(service service.MoneycontrolService, vlog *golog.Log
 }
 
 func (h *MoneyControlHandler) CollectMoneycontrolSymbols(ctx iris.Context) {
-	h.mlog.Info("Moneycontrol symbol collection started")
-	if err := h.moneyControlService.CaptureSymbols(); err != nil {
+	h.mlog.Info("Moneycontrol symbol collection requested")
+	job, err := h.moneyControlService.EnqueueSymbolCollection()
+	if err != nil {
 		failedRes := models.FailedResponse{
 			Status:   iris.StatusInternalServerError,
 			ErrorMsg: "Something went wrong, please try again after some time",
@@ -39,27 +43,50 @@ func (h *MoneyControlHandler) CollectMoneycontrolSymbols(ctx iris.Context) {
 		)
 		return
 	}
-	h.mlog.Info("Moneycontrol symbol collection ended")
-	response := models.Response{
-		Status: "success",
-		Msg:    "Symbols collected successfully",
+	h.mlog.Info(fmt.Sprintf("Moneycontrol symbol collection queued as job %d", job.ID))
+	ctx.StatusCode(iris.StatusAccepted)
+	ctx.JSON(job)
+}
+
+// EnrichCompanies queues a details refresh for the comma separated symbols or NSE IDs in companies,
+// or for every stale company when none are given. force=true refetches fresh companies too.
+func (h *MoneyControlHandler) EnrichCompanies(ctx iris.Context) {
+	var symbols []string
+	for _, symbol := range strings.Split(ctx.URLParam("companies"), ",") {
+		if symbol = strings.TrimSpace(symbol); symbol != "" {
+			symbols = append(symbols, symbol)
+		}
 	}
-	ctx.StatusCode(iris.StatusOK)
-	ctx.JSON(response)
+	force, _ := ctx.URLParamBool("force")
+
+	job, err := h.moneyControlService.EnqueueEnrichment(symbols, force)
+	if err != nil {
+		h.mlog.Error("Error queueing company enrichment", err)
+		ctx.StopWithJSON(iris.StatusInternalServerError, models.FailedResponse{
+			Status:   iris.StatusInternalServerError,
+			ErrorMsg: "Something went wrong, please try again after some time",
+		})
+		return
+	}
+	ctx.StatusCode(iris.StatusAccepted)
+	ctx.JSON(job)
 }
 
 func (h *MoneyControlHandler) CollectDividendData(ctx iris.Context) {
 	company := ctx.URLParam("company")
 
-	h.mlog.Info(fmt.Sprintf("Moneycontrol dividend collection started for %s", company))
-	err := h.moneyControlService.ScrapeDividendHistory(company)
+	h.mlog.Info(fmt.Sprintf("Moneycontrol dividend collection requested for %s", company))
+	job, err := h.moneyControlService.EnqueueDividendCollection(company)
 	if err != nil {
+		if h.ambiguousCompany(ctx, err) {
+			return
+		}
 		var errMsg string
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			errMsg = "Company not found"
 		} else {
 			errMsg = "Something went wrong, please try again after some time"
-			h.mlog.Error(fmt.Sprintf("Error scraping dividend data for %s", company))
+			h.mlog.Error(fmt.Sprintf("Error queueing dividend collection for %s", company))
 		}
 
 		failedRes := models.FailedResponse{
@@ -72,28 +99,89 @@ func (h *MoneyControlHandler) CollectDividendData(ctx iris.Context) {
 		)
 		return
 	}
-	h.mlog.Info(fmt.Sprintf("Dividend history scraped for company %s", company))
+	h.mlog.Info(fmt.Sprintf("Dividend collection for company %s queued as job %d", company, job.ID))
 
-	response := models.Response{
-		Status: "success",
-		Msg:    "Dividend data saved successfully",
+	ctx.StatusCode(iris.StatusAccepted)
+	ctx.JSON(job)
+}
+
+// CollectDividendDataBulk queues a dividend scrape for the comma separated NSE IDs in companies,
+// every company of sector and/or index, or every company when all=true
+func (h *MoneyControlHandler) CollectDividendDataBulk(ctx iris.Context) {
+	selection := bulkSelection(ctx)
+
+	job, err := h.moneyControlService.EnqueueDividendsBulk(selection)
+	if err != nil {
+		if errors.Is(err, service.ErrEmptySelection) {
+			ctx.StopWithJSON(iris.StatusBadRequest, models.FailedResponse{
+				Status:   iris.StatusBadRequest,
+				ErrorMsg: "Provide companies, sector, index or all=true matching at least one company",
+			})
+			return
+		}
+		h.mlog.Error("Error queueing bulk dividend collection", err)
+		ctx.StopWithJSON(iris.StatusInternalServerError, models.FailedResponse{
+			Status:   iris.StatusInternalServerError,
+			ErrorMsg: "Something went wrong, please try again after some time",
+		})
+		return
+	}
+	ctx.StatusCode(iris.StatusAccepted)
+	ctx.JSON(job)
+}
+
+func bulkSelection(ctx iris.Context) models.BulkSelection {
+	var selection models.BulkSelection
+	if companies := ctx.URLParam("companies"); companies != "" {
+		selection.Tickers = strings.Split(companies, ",")
+	}
+	selection.Sector = ctx.URLParam("sector")
+	selection.Index = ctx.URLParam("index")
+	selection.All, _ = ctx.URLParamBool("all")
+	return selection
+}
+
+func (h *MoneyControlHandler) GetDividendHistory(ctx iris.Context) {
+	company := ctx.URLParam("company")
+
+	dividends, err := h.moneyControlService.GetDividendHistory(company)
+	if err != nil {
+		if h.ambiguousCompany(ctx, err) {
+			return
+		}
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ctx.StopWithJSON(iris.StatusNotFound, models.FailedResponse{
+				Status:   iris.StatusNotFound,
+				ErrorMsg: "Company not found",
+			})
+			return
+		}
+		h.mlog.Error(fmt.Sprintf("Error reading dividend history for %s", company), err)
+		ctx.StopWithJSON(iris.StatusInternalServerError, models.FailedResponse{
+			Status:   iris.StatusInternalServerError,
+			ErrorMsg: "Something went wrong, please try again after some time",
+		})
+		return
 	}
 	ctx.StatusCode(iris.StatusOK)
-	ctx.JSON(response)
+	ctx.JSON(dividends)
 }
 
 func (h *MoneyControlHandler) CollectHistoricalDailyDate(ctx iris.Context) {
 	company := ctx.URLParam("company")
 
-	h.mlog.Info(fmt.Sprintf("Moneycontrol historical data collection started for %s", company))
-	err := h.moneyControlService.CaptureHistoricalData(company)
+	h.mlog.Info(fmt.Sprintf("Moneycontrol historical data collection requested for %s", company))
+	job, err := h.moneyControlService.EnqueueHistoricalDataCollection(company)
 	if err != nil {
+		if h.ambiguousCompany(ctx, err) {
+			return
+		}
 		var errMsg string
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			errMsg = "Company not found"
 		} else {
 			errMsg = "Something went wrong, please try again after some time"
-			h.mlog.Error(fmt.Sprintf("Error saving historical daily data for %s", company))
+			h.mlog.Error(fmt.Sprintf("Error queueing historical daily data collection for %s", company))
 		}
 
 		failedRes := models.FailedResponse{
@@ -106,12 +194,200 @@ func (h *MoneyControlHandler) CollectHistoricalDailyDate(ctx iris.Context) {
 		)
 		return
 	}
-	h.mlog.Info(fmt.Sprintf("Historical daily data collected for company %s", company))
+	h.mlog.Info(fmt.Sprintf("Historical daily data collection for company %s queued as job %d", company, job.ID))
+
+	ctx.StatusCode(iris.StatusAccepted)
+	ctx.JSON(job)
+}
+
+func (h *MoneyControlHandler) GetStockPrice(ctx iris.Context) {
+	company := ctx.URLParam("company")
+	timeframe := models.Timeframe(ctx.URLParamDefault("timeframe", string(models.TimeframeDaily)))
 
-	response := models.Response{
-		Status: "success",
-		Msg:    "Historical daily data saved successfully",
+	stockPrice, err := h.moneyControlService.GetPrice(company, timeframe)
+	if err != nil {
+		h.quoteFailed(ctx, company, err)
+		return
 	}
 	ctx.StatusCode(iris.StatusOK)
-	ctx.JSON(response)
+	ctx.JSON(stockPrice)
+}
+
+func (h *MoneyControlHandler) GetStockTechnicals(ctx iris.Context) {
+	company := ctx.URLParam("company")
+	timeframe := models.Timeframe(ctx.URLParamDefault("timeframe", string(models.TimeframeDaily)))
+
+	stockTechnicals, err := h.moneyControlService.GetTechnicals(company, timeframe)
+	if err != nil {
+		h.quoteFailed(ctx, company, err)
+		return
+	}
+	ctx.StatusCode(iris.StatusOK)
+	ctx.JSON(stockTechnicals)
+}
+
+func (h *MoneyControlHandler) GetStockMovingAverages(ctx iris.Context) {
+	company := ctx.URLParam("company")
+	timeframe := models.Timeframe(ctx.URLParamDefault("timeframe", string(models.TimeframeDaily)))
+
+	stockMovingAverage, err := h.moneyControlService.GetMovingAverage(company, timeframe)
+	if err != nil {
+		h.quoteFailed(ctx, company, err)
+		return
+	}
+	ctx.StatusCode(iris.StatusOK)
+	ctx.JSON(stockMovingAverage)
+}
+
+func (h *MoneyControlHandler) GetStockPivotLevels(ctx iris.Context) {
+	company := ctx.URLParam("company")
+	timeframe := models.Timeframe(ctx.URLParamDefault("timeframe", string(models.TimeframeDaily)))
+
+	stockPivotLevels, err := h.moneyControlService.GetPivotLevels(company, timeframe)
+	if err != nil {
+		h.quoteFailed(ctx, company, err)
+		return
+	}
+	ctx.StatusCode(iris.StatusOK)
+	ctx.JSON(stockPivotLevels)
+}
+
+func (h *MoneyControlHandler) GetStockTechnicalSnapshot(ctx iris.Context) {
+	company := ctx.URLParam("company")
+	timeframe := models.Timeframe(ctx.URLParamDefault("timeframe", string(models.TimeframeDaily)))
+
+	snapshot, err := h.moneyControlService.GetTechnicalSnapshot(company, timeframe)
+	if err != nil {
+		h.quoteFailed(ctx, company, err)
+		return
+	}
+	ctx.StatusCode(iris.StatusOK)
+	ctx.JSON(snapshot)
+}
+
+func (h *MoneyControlHandler) StartHistoricalBackfill(ctx iris.Context) {
+	job, err := h.moneyControlService.EnqueueBackfill()
+	if err != nil {
+		if errors.Is(err, service.ErrBackfillRunning) {
+			ctx.StopWithJSON(iris.StatusConflict, models.FailedResponse{
+				Status:   iris.StatusConflict,
+				ErrorMsg: "Historical data backfill is already running",
+			})
+			return
+		}
+		h.mlog.Error("Error starting historical data backfill", err)
+		ctx.StopWithJSON(iris.StatusInternalServerError, models.FailedResponse{
+			Status:   iris.StatusInternalServerError,
+			ErrorMsg: "Something went wrong, please try again after some time",
+		})
+		return
+	}
+	ctx.StatusCode(iris.StatusAccepted)
+	ctx.JSON(job)
+}
+
+func (h *MoneyControlHandler) GetHistoricalBackfillStatus(ctx iris.Context) {
+	run, err := h.moneyControlService.GetBackfillStatus()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ctx.StopWithJSON(iris.StatusNotFound, models.FailedResponse{
+				Status:   iris.StatusNotFound,
+				ErrorMsg: "No backfill has run yet",
+			})
+			return
+		}
+		h.mlog.Error("Error reading historical data backfill status", err)
+		ctx.StopWithJSON(iris.StatusInternalServerError, models.FailedResponse{
+			Status:   iris.StatusInternalServerError,
+			ErrorMsg: "Something went wrong, please try again after some time",
+		})
+		return
+	}
+	ctx.StatusCode(iris.StatusOK)
+	ctx.JSON(run)
+}
+
+func (h *MoneyControlHandler) GetHistoricalData(ctx iris.Context) {
+	company := ctx.URLParam("company")
+
+	from, to, ok := h.dateRange(ctx)
+	if !ok {
+		return
+	}
+
+	candles, err := h.moneyControlService.GetHistoricalData(company, from, to)
+	if err != nil {
+		if h.ambiguousCompany(ctx, err) {
+			return
+		}
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ctx.StopWithJSON(iris.StatusNotFound, models.FailedResponse{
+				Status:   iris.StatusNotFound,
+				ErrorMsg: "Company not found",
+			})
+			return
+		}
+		h.mlog.Error(fmt.Sprintf("Error reading historical data for %s", company), err)
+		ctx.StopWithJSON(iris.StatusInternalServerError, models.FailedResponse{
+			Status:   iris.StatusInternalServerError,
+			ErrorMsg: "Something went wrong, please try again after some time",
+		})
+		return
+	}
+	ctx.StatusCode(iris.StatusOK)
+	ctx.JSON(candles)
+}
+
+// dateRange reads the optional from and to date params in IST, to covering the whole day.
+// It answers with a 400 and reports false when either is malformed.
+func (h *MoneyControlHandler) dateRange(ctx iris.Context) (from, to time.Time, ok bool) {
+	from, to, err := params.DateRange(ctx, service.MarketLocation)
+	if err != nil {
+		ctx.StopWithJSON(iris.StatusBadRequest, models.FailedResponse{
+			Status:   iris.StatusBadRequest,
+			ErrorMsg: err.Error(),
+		})
+		return from, to, false
+	}
+	return from, to, true
+}
+
+// ambiguousCompany answers with a 409 listing the candidates when a company identifier
+// matches more than one company
+func (h *MoneyControlHandler) ambiguousCompany(ctx iris.Context, err error) bool {
+	if !errors.Is(err, service.ErrAmbiguousCompany) {
+		return false
+	}
+	ctx.StopWithJSON(iris.StatusConflict, models.FailedResponse{
+		Status:   iris.StatusConflict,
+		ErrorMsg: err.Error(),
+	})
+	return true
+}
+
+// quoteFailed maps a quote lookup error to a 400 for bad timeframes, a 404 for unknown tickers,
+// a 409 for ambiguous ones and a 500 otherwise
+func (h *MoneyControlHandler) quoteFailed(ctx iris.Context, company string, err error) {
+	if errors.Is(err, service.ErrInvalidTimeframe) {
+		ctx.StopWithJSON(iris.StatusBadRequest, models.FailedResponse{
+			Status:   iris.StatusBadRequest,
+			ErrorMsg: "timeframe must be one of daily, weekly or monthly",
+		})
+		return
+	}
+	if h.ambiguousCompany(ctx, err) {
+		return
+	}
+	if errors.Is(err, service.ErrCompanyNotFound) {
+		ctx.StopWithJSON(iris.StatusNotFound, models.FailedResponse{
+			Status:   iris.StatusNotFound,
+			ErrorMsg: "Company not found",
+		})
+		return
+	}
+	h.mlog.Error(fmt.Sprintf("Error reading stock quote for %s", company), err)
+	ctx.StopWithJSON(iris.StatusInternalServerError, models.FailedResponse{
+		Status:   iris.StatusInternalServerError,
+		ErrorMsg: "Something went wrong, please try again after some time",
+	})
 }
@@ -0,0 +1,61 @@
+package moneycontrolapi
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/johnsonabraham/moneycontrolscraper/internal/moneycontrol/models"
+	service "github.com/johnsonabraham/moneycontrolscraper/internal/moneycontrol/service"
+	"github.com/kataras/iris/v12"
+	"gorm.io/gorm"
+)
+
+// GetPeers returns the companies of the sub-sector of a company ranked by market cap, at most limit
+// of them, with their latest stored price and key ratios
+func (h *MoneyControlHandler) GetPeers(ctx iris.Context) {
+	company := ctx.Params().Get("nseid")
+
+	peers, err := h.moneyControlService.GetPeers(company, ctx.URLParamIntDefault("limit", 0))
+	if err != nil {
+		if h.ambiguousCompany(ctx, err) {
+			return
+		}
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ctx.StopWithJSON(iris.StatusNotFound, models.FailedResponse{
+				Status:   iris.StatusNotFound,
+				ErrorMsg: "Company not found",
+			})
+			return
+		}
+		if errors.Is(err, service.ErrNoSubSector) {
+			ctx.StopWithJSON(iris.StatusNotFound, models.FailedResponse{
+				Status:   iris.StatusNotFound,
+				ErrorMsg: "Company has no sub-sector yet, enrich it first",
+			})
+			return
+		}
+		h.mlog.Error(fmt.Sprintf("Error reading peers of %s", company), err)
+		ctx.StopWithJSON(iris.StatusInternalServerError, models.FailedResponse{
+			Status:   iris.StatusInternalServerError,
+			ErrorMsg: "Something went wrong, please try again after some time",
+		})
+		return
+	}
+	ctx.StatusCode(iris.StatusOK)
+	ctx.JSON(peers)
+}
+
+// GetSectorSummaries returns the listed company count and total market cap of every sector
+func (h *MoneyControlHandler) GetSectorSummaries(ctx iris.Context) {
+	summaries, err := h.moneyControlService.GetSectorSummaries()
+	if err != nil {
+		h.mlog.Error("Error summarising sectors", err)
+		ctx.StopWithJSON(iris.StatusInternalServerError, models.FailedResponse{
+			Status:   iris.StatusInternalServerError,
+			ErrorMsg: "Something went wrong, please try again after some time",
+		})
+		return
+	}
+	ctx.StatusCode(iris.StatusOK)
+	ctx.JSON(summaries)
+}
@@ -0,0 +1,58 @@
+package moneycontrolapi
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/johnsonabraham/moneycontrolscraper/internal/moneycontrol/models"
+	"github.com/kataras/iris/v12"
+	"gorm.io/gorm"
+)
+
+// CollectShareholding queues a scrape of the shareholding pattern of company
+func (h *MoneyControlHandler) CollectShareholding(ctx iris.Context) {
+	company := ctx.URLParam("company")
+
+	h.mlog.Info(fmt.Sprintf("Moneycontrol shareholding collection requested for %s", company))
+	job, err := h.moneyControlService.EnqueueShareholdingCollection(company)
+	if err != nil {
+		h.shareholdingFailed(ctx, company, err)
+		return
+	}
+	h.mlog.Info(fmt.Sprintf("Shareholding collection for company %s queued as job %d", company, job.ID))
+
+	ctx.StatusCode(iris.StatusAccepted)
+	ctx.JSON(job)
+}
+
+// GetShareholding returns the quarterly shareholding pattern of company with quarter over quarter
+// changes and the promoter pledge
+func (h *MoneyControlHandler) GetShareholding(ctx iris.Context) {
+	company := ctx.URLParam("company")
+
+	quarters, err := h.moneyControlService.GetShareholding(company)
+	if err != nil {
+		h.shareholdingFailed(ctx, company, err)
+		return
+	}
+	ctx.StatusCode(iris.StatusOK)
+	ctx.JSON(quarters)
+}
+
+func (h *MoneyControlHandler) shareholdingFailed(ctx iris.Context, company string, err error) {
+	if h.ambiguousCompany(ctx, err) {
+		return
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		ctx.StopWithJSON(iris.StatusNotFound, models.FailedResponse{
+			Status:   iris.StatusNotFound,
+			ErrorMsg: "Company not found",
+		})
+		return
+	}
+	h.mlog.Error(fmt.Sprintf("Error handling shareholding pattern for %s", company), err)
+	ctx.StopWithJSON(iris.StatusInternalServerError, models.FailedResponse{
+		Status:   iris.StatusInternalServerError,
+		ErrorMsg: "Something went wrong, please try again after some time",
+	})
+}
@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// Announcement is a corporate announcement of a company such as a board meeting, results date or
+// AGM notice. Hash identifies its content so a rescrape doesn't store it twice, and the increasing
+// ID is the cursor of incremental polling.
+type Announcement struct {
+	ID         int64     `gorm:"primary_key NOT NULL AUTO_INCREMENT" json:"id"`
+	CompanyID  int64     `gorm:"index:idx_announcement_company_date" json:"company_id"`
+	Date       time.Time `gorm:"index:idx_announcement_company_date" json:"date"`
+	Category   string    `json:"category,omitempty"`
+	Title      string    `json:"title"`
+	Link       string    `json:"link"`
+	Hash       string    `gorm:"uniqueIndex" json:"-"`
+	CapturedAt time.Time `json:"captured_at"`
+}
+
+// AnnouncementFilter narrows an announcement query. Zero fields match every announcement, Since
+// keeps announcements stored after the one with that id.
+type AnnouncementFilter struct {
+	CompanyID int64
+	From      time.Time
+	To        time.Time
+	Since     int64
+	Limit     int
+}
+
+// AnnouncementPage is a batch of announcements oldest stored first. NextSince is the since cursor
+// that polls the announcements stored after this batch. Announcements are only paged once they were
+// captured a minute ago, since ids are handed out before the insert commits and an announcement
+// committing late behind a higher id would otherwise be skipped.
+type AnnouncementPage struct {
+	Announcements []Announcement `json:"announcements"`
+	NextSince     int64          `json:"next_since"`
+}
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// BackfillRun tracks one pass of the historical data backfill over every NSE listed company.
+// A run without FinishedAt is resumed instead of starting a new one.
+type BackfillRun struct {
+	ID         int64      `gorm:"primary_key NOT NULL AUTO_INCREMENT" json:"id"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+	Total      int        `json:"total"`
+	Succeeded  int        `json:"succeeded"`
+	Skipped    int        `json:"skipped"`
+	Failed     int        `json:"failed"`
+}
+
+// BackfillProgress records the outcome of a company within a backfill run
+type BackfillProgress struct {
+	ID        int64     `gorm:"primary_key NOT NULL AUTO_INCREMENT" json:"-"`
+	RunID     int64     `gorm:"uniqueIndex:idx_backfill_run_company" json:"run_id"`
+	CompanyID int64     `gorm:"uniqueIndex:idx_backfill_run_company" json:"company_id"`
+	Status    string    `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+const (
+	BackfillSucceeded = "success"
+	BackfillSkipped   = "skipped"
+	BackfillFailed    = "failed"
+)
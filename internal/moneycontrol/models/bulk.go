@@ -0,0 +1,24 @@
+package models
+
+// BulkSelection picks the companies of a bulk collection: explicit tickers, the companies of a sector
+// and/or index, or every company
+type BulkSelection struct {
+	Tickers []string
+	Sector  string
+	Index   string
+	All     bool
+}
+
+type BulkResult struct {
+	Ticker string `json:"ticker"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// BulkReport is the per ticker outcome of a bulk collection
+type BulkReport struct {
+	Total     int          `json:"total"`
+	Succeeded int          `json:"succeeded"`
+	Failed    int          `json:"failed"`
+	Results   []BulkResult `json:"results"`
+}
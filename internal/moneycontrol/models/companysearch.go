@@ -0,0 +1,34 @@
+package models
+
+const (
+	SortByName          = "name"
+	SortByMarketCapDesc = "market_cap_desc"
+	SortByMarketCapAsc  = "market_cap_asc"
+	SortByPEAsc         = "pe_asc"
+	SortByPEDesc        = "pe_desc"
+)
+
+// CompanyFilter narrows and orders a company directory listing. Query matches every whitespace
+// separated word against Company, CompanyName, NSEID, BSEID and ISIN. Index keeps the constituents
+// of the named index. MinPE and MaxPE bound the P/E ratio when set, leaving out companies without one.
+type CompanyFilter struct {
+	Query           string
+	Sector          string
+	MainSector      string
+	SubSector       string
+	Index           string
+	MinPE           float64
+	MaxPE           float64
+	IncludeDelisted bool
+	Sort            string
+	Page            int
+	PageSize        int
+}
+
+// CompanyPage is one page of a company directory listing
+type CompanyPage struct {
+	Total     int64         `json:"total"`
+	Page      int           `json:"page"`
+	PageSize  int           `json:"page_size"`
+	Companies []CompanyInfo `json:"companies"`
+}
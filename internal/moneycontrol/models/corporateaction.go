@@ -0,0 +1,29 @@
+package models
+
+const (
+	CorporateActionBonus  = "bonus"
+	CorporateActionSplit  = "split"
+	CorporateActionRights = "rights"
+)
+
+// CorporateActionTypes lists every corporate action scraped for a company
+var CorporateActionTypes = []string{CorporateActionBonus, CorporateActionSplit, CorporateActionRights}
+
+// CorporateAction is a bonus issue, stock split or rights issue of a company. Ratio is the ratio as
+// published, "new:held" for bonus and rights issues. AdjustmentFactor is what prices before the ex
+// date divide by to line up with prices after it, 0 for rights issues whose factor depends on the
+// market price.
+type CorporateAction struct {
+	ID               int64   `gorm:"primary_key NOT NULL AUTO_INCREMENT" json:"-"`
+	CompanyID        int64   `gorm:"uniqueIndex:idx_corporate_action_company_type_ex_date" json:"-"`
+	Type             string  `gorm:"uniqueIndex:idx_corporate_action_company_type_ex_date" json:"type"`
+	AnnouncementDate int64   `json:"announcement_date"`
+	RecordDate       int64   `json:"record_date"`
+	ExDate           int64   `gorm:"uniqueIndex:idx_corporate_action_company_type_ex_date" json:"ex_date"`
+	Ratio            string  `json:"ratio"`
+	OldFaceValue     float64 `json:"old_face_value"`
+	NewFaceValue     float64 `json:"new_face_value"`
+	Premium          float64 `json:"premium"`
+	AdjustmentFactor float64 `json:"adjustment_factor"`
+	Remark           string  `json:"remark"`
+}
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+const (
+	StatementQuarterlyResults = "quarterly_results"
+	StatementProfitLoss       = "profit_loss"
+	StatementBalanceSheet     = "balance_sheet"
+	StatementCashFlow         = "cash_flow"
+)
+
+// Statements lists every financial statement scraped for a company
+var Statements = []string{StatementQuarterlyResults, StatementProfitLoss, StatementBalanceSheet, StatementCashFlow}
+
+// FinancialLineItem is one value of a standalone financial statement of a company. Period is the
+// month the period ends in as YYYY-MM, Value is in Rs. Cr. unless the line item says otherwise and
+// nil where moneycontrol shows no figure. Section is the heading the line item is listed under.
+type FinancialLineItem struct {
+	ID        int64     `gorm:"primary_key NOT NULL AUTO_INCREMENT" json:"-"`
+	CompanyID int64     `gorm:"uniqueIndex:idx_financial_company_statement_period_item" json:"-"`
+	Statement string    `gorm:"uniqueIndex:idx_financial_company_statement_period_item" json:"statement"`
+	Period    string    `gorm:"uniqueIndex:idx_financial_company_statement_period_item" json:"period"`
+	LineItem  string    `gorm:"uniqueIndex:idx_financial_company_statement_period_item" json:"line_item"`
+	Section   string    `json:"section,omitempty"`
+	Position  int       `json:"position"`
+	Value     *float64  `json:"value"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
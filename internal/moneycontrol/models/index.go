@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// Index is a market index such as NIFTY 50 or SENSEX. LevelCode identifies it in the moneycontrol
+// price feed and ConstituentsPage is the path of its constituents page, such as
+// "top-nse-50-companies-list/9".
+type Index struct {
+	ID               int64      `gorm:"primary_key NOT NULL AUTO_INCREMENT" json:"id"`
+	Name             string     `gorm:"uniqueIndex" json:"name"`
+	LevelCode        string     `json:"level_code"`
+	ConstituentsPage string     `json:"constituents_page"`
+	Level            float64    `json:"level"`
+	Change           float64    `json:"change"`
+	ChangePercent    float64    `json:"change_percent"`
+	LevelAt          *time.Time `json:"level_at"`
+	ConstituentsAt   *time.Time `json:"constituents_at"`
+}
+
+// IndexConstituent links a company to an index it belongs to, Weight being its weight in the index
+// in percent
+type IndexConstituent struct {
+	ID        int64   `gorm:"primary_key NOT NULL AUTO_INCREMENT" json:"-"`
+	IndexID   int64   `gorm:"uniqueIndex:idx_index_constituent" json:"-"`
+	CompanyID int64   `gorm:"uniqueIndex:idx_index_constituent;index" json:"-"`
+	Weight    float64 `json:"weight"`
+}
+
+// Constituent is a company of an index with its weight
+type Constituent struct {
+	Weight  float64     `json:"weight"`
+	Company CompanyInfo `json:"company"`
+}
@@ -0,0 +1,64 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	JobQueued    = "queued"
+	JobRunning   = "running"
+	JobSucceeded = "succeeded"
+	JobFailed    = "failed"
+)
+
+const (
+	JobCollectSymbols          = "collect_symbols"
+	JobEnrichCompanies         = "enrich_companies"
+	JobCollectDividends        = "collect_dividends"
+	JobCollectDividendsBulk    = "collect_dividends_bulk"
+	JobCollectHistoricalData   = "collect_historical_data"
+	JobCollectFinancials       = "collect_financials"
+	JobCollectCorporateActions = "collect_corporate_actions"
+	JobCollectShareholding     = "collect_shareholding"
+	JobCollectAnnouncements    = "collect_announcements"
+	JobCollectMarketDepth      = "collect_market_depth"
+	JobBackfillHistoricalData  = "backfill_historical_data"
+	JobCollectIndices          = "collect_indices"
+	JobCollectMFSchemes        = "collect_mf_schemes"
+	JobCollectMFNAVHistory     = "collect_mf_nav_history"
+)
+
+// LongJobTypes are the jobs going through every company or scheme, which run on their own workers
+// so they can't hold up the per-company collections
+var LongJobTypes = map[string]bool{
+	JobCollectSymbols:         true,
+	JobEnrichCompanies:        true,
+	JobCollectDividendsBulk:   true,
+	JobBackfillHistoricalData: true,
+	JobCollectMFSchemes:       true,
+}
+
+// Job tracks a long running scrape queued through the API. Total, Completed and Failed count
+// the units of work (usually companies) the job goes through.
+type Job struct {
+	ID         int64           `gorm:"primary_key NOT NULL AUTO_INCREMENT" json:"id"`
+	Type       string          `gorm:"index" json:"type"`
+	Params     string          `json:"params,omitempty"`
+	State      string          `gorm:"index" json:"state"`
+	Total      int             `json:"total"`
+	Completed  int             `json:"completed"`
+	Failed     int             `json:"failed"`
+	Error      string          `json:"error,omitempty"`
+	Result     json.RawMessage `gorm:"type:jsonb" json:"result,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+	StartedAt  *time.Time      `json:"started_at"`
+	FinishedAt *time.Time      `json:"finished_at"`
+}
+
+// JobFilter narrows a job listing, empty fields matching every job
+type JobFilter struct {
+	Type  string
+	State string
+	Limit int
+}
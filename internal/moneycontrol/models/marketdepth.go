@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// DepthLevel is one level of the order book, level 1 holding the best bid and ask
+type DepthLevel struct {
+	Level       int     `json:"level"`
+	BidPrice    float64 `json:"bid_price"`
+	BidQuantity int64   `json:"bid_quantity"`
+	AskPrice    float64 `json:"ask_price"`
+	AskQuantity int64   `json:"ask_quantity"`
+}
+
+// MarketDepth is a snapshot of the best five bid and ask levels of a company taken from the
+// moneycontrol company details API
+type MarketDepth struct {
+	ID               int64        `gorm:"primary_key NOT NULL AUTO_INCREMENT" json:"-"`
+	CompanyID        int64        `gorm:"index:idx_market_depth_company_captured_at" json:"-"`
+	CapturedAt       time.Time    `gorm:"index:idx_market_depth_company_captured_at" json:"captured_at"`
+	Levels           []DepthLevel `gorm:"serializer:json;type:jsonb" json:"levels"`
+	TotalBidQuantity int64        `json:"total_bid_quantity"`
+	TotalAskQuantity int64        `json:"total_ask_quantity"`
+}
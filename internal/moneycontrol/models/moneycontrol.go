@@ -1,14 +1,43 @@
 package models
 
+import (
+	"encoding/json"
+	"time"
+)
+
+// Timeframe is the interval moneycontrol computes technical indicators over
+type Timeframe string
+
+const (
+	TimeframeDaily   Timeframe = "daily"
+	TimeframeWeekly  Timeframe = "weekly"
+	TimeframeMonthly Timeframe = "monthly"
+)
+
+// Dividend is a dividend of a company, its dates being the unix time of the start of the day in IST
 type Dividend struct {
+	ID                 int64 `gorm:"primary_key NOT NULL AUTO_INCREMENT" json:"-"`
+	CompanyID          int64 `gorm:"uniqueIndex:idx_dividend_company_ex_date" json:"-"`
 	AnnouncementDate   int64
-	ExDate             int64
+	ExDate             int64 `gorm:"uniqueIndex:idx_dividend_company_ex_date"`
 	DividendType       string
 	DividendPercentage float64
 	Dividend           float64
 	Remark             string
 }
 
+// Candle is one daily OHLCV bar of a company, Timestamp being the unix time of the trading day
+type Candle struct {
+	ID        int64 `gorm:"primary_key NOT NULL AUTO_INCREMENT" json:"-"`
+	CompanyID int64 `gorm:"uniqueIndex:idx_candle_company_date" json:"-"`
+	Timestamp int64 `gorm:"uniqueIndex:idx_candle_company_date"`
+	Open      float64
+	High      float64
+	Low       float64
+	Close     float64
+	Volume    int64
+}
+
 type CompanyInfo struct {
 	ID                int64 `gorm:"primary_key NOT NULL AUTO_INCREMENT"`
 	CompanyName       string
@@ -20,7 +49,18 @@ type CompanyInfo struct {
 	MainSectorDetails string
 	SubSectorDetails  string
 	BSEID             string
-	MoreData          string
+	// MoreData is the raw company details payload the typed fields below are read from. It stays out of
+	// responses, being several kilobytes per company.
+	MoreData   json.RawMessage `gorm:"type:jsonb" json:"-"`
+	Week52High float64
+	Week52Low  float64
+	PE         float64
+	IndustryPE float64
+	BookValue  float64
+	FaceValue  float64
+	ISIN       string     `gorm:"index"`
+	EnrichedAt *time.Time `gorm:"index"`
+	DelistedAt *time.Time `gorm:"index"`
 }
 
 type (
@@ -42,6 +82,15 @@ type (
 	// StockPivotLevels stores a stock pivote levels
 	StockPivotLevels map[string]PivotPointsValue
 
+	// TechnicalSnapshot holds price, technicals, moving averages and pivot levels read from one page fetch
+	TechnicalSnapshot struct {
+		Price          StockPrice
+		Technicals     StockTechnicals
+		MovingAverages StockMovingAverage
+		PivotLevels    StockPivotLevels
+		FetchedAt      time.Time
+	}
+
 	StockURLValue struct {
 		Sector  string
 		Company string
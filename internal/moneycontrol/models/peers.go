@@ -0,0 +1,38 @@
+package models
+
+// Peer is a company of a sub-sector ranked by market cap, with its latest stored close and key
+// ratios. Rank is 0 for a delisted company, which isn't ranked. Price and PriceAt (unix seconds)
+// are nil until candles of the company are collected, and PriceToBook until both the price and book
+// value are known.
+type Peer struct {
+	Rank        int      `json:"rank"`
+	Self        bool     `json:"self"`
+	ID          int64    `json:"id"`
+	Company     string   `json:"company"`
+	CompanyName string   `json:"company_name"`
+	NSEID       string   `json:"nse_id"`
+	BSEID       string   `json:"bse_id"`
+	MarketCap   float64  `json:"market_cap"`
+	Price       *float64 `json:"price"`
+	PriceAt     *int64   `json:"price_at"`
+	PE          float64  `json:"pe"`
+	IndustryPE  float64  `json:"industry_pe"`
+	BookValue   float64  `json:"book_value"`
+	PriceToBook *float64 `json:"price_to_book"`
+	Week52High  float64  `json:"week_52_high"`
+	Week52Low   float64  `json:"week_52_low"`
+}
+
+// PeerGroup is the sub-sector of a company and its largest members
+type PeerGroup struct {
+	SubSector string `json:"sub_sector"`
+	Total     int64  `json:"total"`
+	Peers     []Peer `json:"peers"`
+}
+
+// SectorSummary counts the listed companies of a sector and totals their market cap
+type SectorSummary struct {
+	Sector    string  `json:"sector"`
+	Companies int64   `json:"companies"`
+	MarketCap float64 `json:"market_cap"`
+}
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+const (
+	ScheduleTriggered = "triggered"
+	ScheduleSkipped   = "skipped"
+	ScheduleFailed    = "failed"
+)
+
+// ScheduleRun records one firing of a scheduled scrape and the job it queued, or the job still in
+// progress that made it skip
+type ScheduleRun struct {
+	ID          int64     `gorm:"primary_key NOT NULL AUTO_INCREMENT" json:"id"`
+	Name        string    `gorm:"index" json:"name"`
+	TriggeredAt time.Time `json:"triggered_at"`
+	Outcome     string    `json:"outcome"`
+	JobID       int64     `json:"job_id,omitempty"`
+	Error       string    `json:"error,omitempty"`
+}
+
+// ScheduleStatus describes a configured schedule with its last firing and the state of that job
+type ScheduleStatus struct {
+	Name    string       `json:"name"`
+	Spec    string       `json:"spec"`
+	NextRun time.Time    `json:"next_run"`
+	LastRun *ScheduleRun `json:"last_run,omitempty"`
+	LastJob *Job         `json:"last_job,omitempty"`
+}
@@ -0,0 +1,31 @@
+package models
+
+const (
+	HolderPromoter       = "promoter"
+	HolderFII            = "fii"
+	HolderDII            = "dii"
+	HolderMutualFunds    = "mutual_funds"
+	HolderRetail         = "retail"
+	HolderOthers         = "others"
+	HolderPromoterPledge = "promoter_pledge"
+)
+
+// Shareholding is the percentage of a company held by a holder category at the end of a quarter,
+// Quarter being the month it ends in as YYYY-MM. The promoter_pledge category holds the percentage
+// of promoter shares pledged rather than a share of the company.
+type Shareholding struct {
+	ID         int64   `gorm:"primary_key NOT NULL AUTO_INCREMENT" json:"-"`
+	CompanyID  int64   `gorm:"uniqueIndex:idx_shareholding_company_quarter_category" json:"-"`
+	Quarter    string  `gorm:"uniqueIndex:idx_shareholding_company_quarter_category" json:"quarter"`
+	Category   string  `gorm:"uniqueIndex:idx_shareholding_company_quarter_category" json:"category"`
+	Percentage float64 `json:"percentage"`
+}
+
+// ShareholdingQuarter is the shareholding pattern of a company in one quarter. Changes holds the
+// percentage point change of each category since the previous stored quarter.
+type ShareholdingQuarter struct {
+	Quarter        string             `json:"quarter"`
+	Holdings       map[string]float64 `json:"holdings"`
+	Changes        map[string]float64 `json:"changes,omitempty"`
+	PromoterPledge *float64           `json:"promoter_pledge"`
+}
@@ -0,0 +1,16 @@
+package models
+
+// SymbolRename is a symbol whose company name changed between two crawls
+type SymbolRename struct {
+	Symbol     string `json:"symbol"`
+	OldCompany string `json:"old_company"`
+	NewCompany string `json:"new_company"`
+}
+
+// SymbolDiff is what a symbol refresh changed compared to the stored companies
+type SymbolDiff struct {
+	Added    []string       `json:"added"`
+	Removed  []string       `json:"removed"`
+	Relisted []string       `json:"relisted"`
+	Renamed  []SymbolRename `json:"renamed"`
+}
@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"github.com/johnsonabraham/moneycontrolscraper/internal/moneycontrol/models"
+	"gorm.io/gorm/clause"
+)
+
+// InsertAnnouncements stores the announcements not stored yet, matching them by content hash,
+// and returns how many were new
+func (s *moneycontrolRepository) InsertAnnouncements(announcements []models.Announcement) (int64, error) {
+	if len(announcements) == 0 {
+		return 0, nil
+	}
+	result := s.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "hash"}},
+		DoNothing: true,
+	}).CreateInBatches(&announcements, 500)
+	if result.Error != nil {
+		s.vlog.Error(result.Error)
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
+
+// FetchAnnouncements returns the announcements matching filter in the order they were stored
+func (s *moneycontrolRepository) FetchAnnouncements(filter models.AnnouncementFilter) ([]models.Announcement, error) {
+	var announcements []models.Announcement
+	query := s.db.Where("id > ?", filter.Since)
+	if filter.CompanyID != 0 {
+		query = query.Where("company_id = ?", filter.CompanyID)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("date >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("date <= ?", filter.To)
+	}
+	err := query.Order("id").Limit(filter.Limit).Find(&announcements).Error
+	if err != nil {
+		s.vlog.Error(err)
+		return nil, err
+	}
+	return announcements, nil
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"github.com/johnsonabraham/moneycontrolscraper/internal/moneycontrol/models"
+	"gorm.io/gorm/clause"
+)
+
+// UpsertCorporateActions stores the corporate actions of a company, overwriting rows of the same
+// type and ex date
+func (s *moneycontrolRepository) UpsertCorporateActions(companyID int64, actions []models.CorporateAction) error {
+	if len(actions) == 0 {
+		return nil
+	}
+	type actionKey struct {
+		actionType string
+		exDate     int64
+	}
+	byKey := make(map[actionKey]int, len(actions))
+	var rows []models.CorporateAction
+	for _, action := range actions {
+		action.ID = 0
+		action.CompanyID = companyID
+		key := actionKey{action.Type, action.ExDate}
+		if idx, found := byKey[key]; found {
+			rows[idx] = action
+			continue
+		}
+		byKey[key] = len(rows)
+		rows = append(rows, action)
+	}
+	err := s.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "company_id"}, {Name: "type"}, {Name: "ex_date"}},
+		DoUpdates: clause.AssignmentColumns([]string{"announcement_date", "record_date", "ratio", "old_face_value",
+			"new_face_value", "premium", "adjustment_factor", "remark"}),
+	}).CreateInBatches(&rows, 500).Error
+	if err != nil {
+		s.vlog.Error(err)
+		return err
+	}
+	return nil
+}
+
+// FetchCorporateActions returns the corporate actions of a company newest first, optionally
+// limited to one type
+func (s *moneycontrolRepository) FetchCorporateActions(companyID int64, actionType string) ([]models.CorporateAction, error) {
+	var actions []models.CorporateAction
+	query := s.db.Where("company_id = ?", companyID)
+	if actionType != "" {
+		query = query.Where("type = ?", actionType)
+	}
+	err := query.Order("ex_date desc").Find(&actions).Error
+	if err != nil {
+		s.vlog.Error(err)
+		return nil, err
+	}
+	return actions, nil
+}
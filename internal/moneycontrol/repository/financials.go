@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"github.com/johnsonabraham/moneycontrolscraper/internal/moneycontrol/models"
+	"gorm.io/gorm/clause"
+)
+
+// UpsertFinancials stores financial statement values of a company, overwriting the value of a
+// line item already stored for the same statement and period
+func (s *moneycontrolRepository) UpsertFinancials(companyID int64, items []models.FinancialLineItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+	type itemKey struct{ statement, period, lineItem string }
+	byKey := make(map[itemKey]int, len(items))
+	var rows []models.FinancialLineItem
+	for _, item := range items {
+		item.ID = 0
+		item.CompanyID = companyID
+		key := itemKey{item.Statement, item.Period, item.LineItem}
+		if idx, found := byKey[key]; found {
+			rows[idx] = item
+			continue
+		}
+		byKey[key] = len(rows)
+		rows = append(rows, item)
+	}
+	err := s.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "company_id"}, {Name: "statement"}, {Name: "period"}, {Name: "line_item"}},
+		DoUpdates: clause.AssignmentColumns([]string{"section", "position", "value", "updated_at"}),
+	}).CreateInBatches(&rows, 500).Error
+	if err != nil {
+		s.vlog.Error(err)
+		return err
+	}
+	return nil
+}
+
+// FetchFinancials returns the stored financial statements of a company, optionally limited to a
+// statement and a YYYY-MM period, newest period first in statement order
+func (s *moneycontrolRepository) FetchFinancials(companyID int64, statement, period string) ([]models.FinancialLineItem, error) {
+	var items []models.FinancialLineItem
+	query := s.db.Where("company_id = ?", companyID)
+	if statement != "" {
+		query = query.Where("statement = ?", statement)
+	}
+	if period != "" {
+		query = query.Where("period = ?", period)
+	}
+	err := query.Order("statement").Order("period desc").Order("position").Find(&items).Error
+	if err != nil {
+		s.vlog.Error(err)
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"strings"
+
+	"github.com/johnsonabraham/moneycontrolscraper/internal/moneycontrol/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// UpsertIndex stores an index by name, updating its codes when it exists, and fills in its id
+func (s *moneycontrolRepository) UpsertIndex(index *models.Index) error {
+	err := s.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"level_code", "constituents_page"}),
+	}).Create(index).Error
+	if err != nil {
+		s.vlog.Error(err)
+		return err
+	}
+	return nil
+}
+
+func (s *moneycontrolRepository) SaveIndex(index *models.Index) error {
+	err := s.db.Save(index).Error
+	if err != nil {
+		s.vlog.Error(err)
+	}
+	return err
+}
+
+// FetchIndices returns every index ordered by name
+func (s *moneycontrolRepository) FetchIndices() ([]models.Index, error) {
+	var indices []models.Index
+	err := s.db.Order("name").Find(&indices).Error
+	if err != nil {
+		s.vlog.Error(err)
+		return nil, err
+	}
+	return indices, nil
+}
+
+func (s *moneycontrolRepository) FetchIndex(id int64) (*models.Index, error) {
+	var index models.Index
+	err := s.db.First(&index, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &index, nil
+}
+
+// ReplaceIndexConstituents makes constituents the members of an index, dropping companies that
+// left it
+func (s *moneycontrolRepository) ReplaceIndexConstituents(indexID int64, constituents []models.IndexConstituent) error {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		byCompany := make(map[int64]int, len(constituents))
+		var rows []models.IndexConstituent
+		companyIDs := []int64{}
+		for _, constituent := range constituents {
+			constituent.ID = 0
+			constituent.IndexID = indexID
+			if idx, found := byCompany[constituent.CompanyID]; found {
+				rows[idx] = constituent
+				continue
+			}
+			byCompany[constituent.CompanyID] = len(rows)
+			rows = append(rows, constituent)
+			companyIDs = append(companyIDs, constituent.CompanyID)
+		}
+		query := tx.Where("index_id = ?", indexID)
+		if len(companyIDs) > 0 {
+			query = query.Where("company_id NOT IN ?", companyIDs)
+		}
+		if er := query.Delete(&models.IndexConstituent{}).Error; er != nil {
+			return er
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "index_id"}, {Name: "company_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"weight"}),
+		}).CreateInBatches(&rows, 500).Error
+	})
+	if err != nil {
+		s.vlog.Error(err)
+		return err
+	}
+	return nil
+}
+
+// FetchIndexConstituents returns the companies of an index, heaviest first
+func (s *moneycontrolRepository) FetchIndexConstituents(indexID int64) ([]models.Constituent, error) {
+	var members []models.IndexConstituent
+	err := s.db.Where("index_id = ?", indexID).Order("weight desc").Find(&members).Error
+	if err != nil {
+		s.vlog.Error(err)
+		return nil, err
+	}
+	companyIDs := make([]int64, len(members))
+	for idx, member := range members {
+		companyIDs[idx] = member.CompanyID
+	}
+	var companies []models.CompanyInfo
+	if len(companyIDs) > 0 {
+		if err := s.db.Where("id IN ?", companyIDs).Find(&companies).Error; err != nil {
+			s.vlog.Error(err)
+			return nil, err
+		}
+	}
+	byID := make(map[int64]models.CompanyInfo, len(companies))
+	for _, company := range companies {
+		byID[company.ID] = company
+	}
+	constituents := make([]models.Constituent, 0, len(members))
+	for _, member := range members {
+		constituents = append(constituents, models.Constituent{Weight: member.Weight, Company: byID[member.CompanyID]})
+	}
+	return constituents, nil
+}
+
+// FetchCompanyIDsBySymbols maps moneycontrol symbols to company ids, leaving out unknown symbols
+func (s *moneycontrolRepository) FetchCompanyIDsBySymbols(symbols []string) (map[string]int64, error) {
+	ids := make(map[string]int64, len(symbols))
+	if len(symbols) == 0 {
+		return ids, nil
+	}
+	var companies []models.CompanyInfo
+	err := s.db.Select("id", "symbol").Where("symbol IN ?", symbols).Find(&companies).Error
+	if err != nil {
+		s.vlog.Error(err)
+		return nil, err
+	}
+	for _, company := range companies {
+		ids[company.Symbol] = company.ID
+	}
+	return ids, nil
+}
+
+// indexMembers is the subquery of the company ids of the index named name, case insensitively
+func (s *moneycontrolRepository) indexMembers(name string) *gorm.DB {
+	return s.db.Model(&models.IndexConstituent{}).Select("index_constituents.company_id").
+		Joins("JOIN indices ON indices.id = index_constituents.index_id").
+		Where("lower(indices.name) = ?", strings.ToLower(name))
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/johnsonabraham/moneycontrolscraper/internal/moneycontrol/models"
+	"github.com/kataras/golog"
+	"gorm.io/gorm"
+)
+
+type JobRepository interface {
+	CreateJob(job *models.Job) error
+	SaveJob(job *models.Job) error
+	FetchJob(id int64) (*models.Job, error)
+	FetchJobs(filter models.JobFilter) ([]models.Job, error)
+	FailUnfinishedJobs(reason string) error
+	CreateScheduleRun(run *models.ScheduleRun) error
+	FetchLatestScheduleRun(name string) (*models.ScheduleRun, error)
+	FetchLatestScheduleRunWithJob(name string) (*models.ScheduleRun, error)
+}
+
+type jobRepository struct {
+	db   *gorm.DB
+	vlog *golog.Logger
+}
+
+func NewJobRepository(db *gorm.DB, vlog *golog.Logger) *jobRepository {
+	return &jobRepository{
+		db:   db,
+		vlog: vlog,
+	}
+}
+
+func (s *jobRepository) CreateJob(job *models.Job) error {
+	err := s.db.Create(job).Error
+	if err != nil {
+		s.vlog.Error(err)
+	}
+	return err
+}
+
+func (s *jobRepository) SaveJob(job *models.Job) error {
+	err := s.db.Save(job).Error
+	if err != nil {
+		s.vlog.Error(err)
+	}
+	return err
+}
+
+func (s *jobRepository) FetchJob(id int64) (*models.Job, error) {
+	var job models.Job
+	err := s.db.First(&job, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// FetchJobs returns the newest jobs first
+func (s *jobRepository) FetchJobs(filter models.JobFilter) ([]models.Job, error) {
+	var jobs []models.Job
+	query := s.db.Order("id desc")
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+	if filter.State != "" {
+		query = query.Where("state = ?", filter.State)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	err := query.Find(&jobs).Error
+	if err != nil {
+		s.vlog.Error(err)
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// FailUnfinishedJobs marks queued and running jobs failed, used at startup since their work died with
+// the previous process
+func (s *jobRepository) FailUnfinishedJobs(reason string) error {
+	err := s.db.Model(&models.Job{}).Where("state IN ?", []string{models.JobQueued, models.JobRunning}).
+		Updates(map[string]interface{}{"state": models.JobFailed, "error": reason, "finished_at": time.Now()}).Error
+	if err != nil {
+		s.vlog.Error(err)
+	}
+	return err
+}
+
+func (s *jobRepository) CreateScheduleRun(run *models.ScheduleRun) error {
+	err := s.db.Create(run).Error
+	if err != nil {
+		s.vlog.Error(err)
+	}
+	return err
+}
+
+func (s *jobRepository) FetchLatestScheduleRun(name string) (*models.ScheduleRun, error) {
+	var run models.ScheduleRun
+	err := s.db.Where("name = ?", name).Order("id desc").First(&run).Error
+	if err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+// FetchLatestScheduleRunWithJob returns the last firing of a schedule that queued a job or was
+// skipped for one still in progress
+func (s *jobRepository) FetchLatestScheduleRunWithJob(name string) (*models.ScheduleRun, error) {
+	var run models.ScheduleRun
+	err := s.db.Where("name = ? AND job_id <> 0", name).Order("id desc").First(&run).Error
+	if err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
@@ -1,19 +1,70 @@
 package repository
 
 import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/johnsonabraham/moneycontrolscraper/config"
 	"github.com/johnsonabraham/moneycontrolscraper/internal/moneycontrol/models"
+	"github.com/johnsonabraham/moneycontrolscraper/pkg/sqlutil"
 	"github.com/kataras/golog"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
 type MoneycontrolRepository interface {
-	InsertMoneyControlSymbols([]models.CompanyInfo) error
+	RefreshMoneyControlSymbols([]models.CompanyInfo) (models.SymbolDiff, error)
 	FetchCompanyByNameConstant(companyName string) (*models.CompanyInfo, error)
 	UpdateSymbol(result models.CompanyInfo) error
+	FetchCompanies() ([]models.CompanyInfo, error)
+	FetchCompaniesToEnrich(symbols []string, staleBefore time.Time) ([]models.CompanyInfo, error)
+	ResolveCompany(identifier string) (*models.CompanyInfo, error)
+	FetchExchangeIDs(sector, index string) ([]string, error)
+	SearchCompanies(filter models.CompanyFilter) ([]models.CompanyInfo, int64, error)
+	FetchSubSectorCompanies(subSector string) ([]models.CompanyInfo, error)
+	FetchSectorSummaries() ([]models.SectorSummary, error)
+	UpsertIndex(index *models.Index) error
+	SaveIndex(index *models.Index) error
+	FetchIndices() ([]models.Index, error)
+	FetchIndex(id int64) (*models.Index, error)
+	ReplaceIndexConstituents(indexID int64, constituents []models.IndexConstituent) error
+	FetchIndexConstituents(indexID int64) ([]models.Constituent, error)
+	FetchCompanyIDsBySymbols(symbols []string) (map[string]int64, error)
+	UpsertDividends(companyID int64, dividends []models.Dividend) error
+	FetchDividends(companyID int64) ([]models.Dividend, error)
+	UpsertCorporateActions(companyID int64, actions []models.CorporateAction) error
+	FetchCorporateActions(companyID int64, actionType string) ([]models.CorporateAction, error)
+	UpsertShareholdings(companyID int64, holdings []models.Shareholding) error
+	FetchShareholdings(companyID int64) ([]models.Shareholding, error)
+	InsertAnnouncements(announcements []models.Announcement) (int64, error)
+	FetchAnnouncements(filter models.AnnouncementFilter) ([]models.Announcement, error)
+	UpsertCandles(companyID int64, candles []models.Candle) error
+	FetchCandles(companyID int64, from, to int64) ([]models.Candle, error)
+	FetchLatestCandles(companyIDs []int64) (map[int64]models.Candle, error)
+	UpsertFinancials(companyID int64, items []models.FinancialLineItem) error
+	FetchFinancials(companyID int64, statement, period string) ([]models.FinancialLineItem, error)
+	SaveMarketDepth(depth *models.MarketDepth) error
+	FetchMarketDepth(companyID int64, from, to time.Time, limit int) ([]models.MarketDepth, error)
+	FetchLatestCandleTimestamps() (map[int64]int64, error)
+	CreateBackfillRun(run *models.BackfillRun) error
+	SaveBackfillRun(run *models.BackfillRun) error
+	FetchLatestBackfillRun() (*models.BackfillRun, error)
+	SaveBackfillProgress(progress models.BackfillProgress) error
+	FetchBackfillStatuses(runID int64) (map[int64]string, error)
 }
 
+var (
+	errEmptySymbolRefresh = errors.New("refusing to refresh symbols from an empty crawl")
+
+	// ErrAmbiguousCompany is returned when an identifier matches more than one company
+	ErrAmbiguousCompany = errors.New("identifier matches more than one company")
+
+	// maxResolveCandidates caps the companies listed in an ambiguity error
+	maxResolveCandidates = 10
+)
+
 type moneycontrolRepository struct {
 	db      *gorm.DB
 	envVars *config.AppEnvVars
@@ -28,23 +79,73 @@ func NewMoneycontrolRepository(db *gorm.DB, vlog *golog.Logger, envVars *config.
 	}
 }
 
-func (s *moneycontrolRepository) InsertMoneyControlSymbols(result []models.CompanyInfo) error {
+// RefreshMoneyControlSymbols upserts the crawled companies by symbol, keeping the columns written by
+// enrichment, and marks stored symbols missing from the crawl as delisted
+func (s *moneycontrolRepository) RefreshMoneyControlSymbols(result []models.CompanyInfo) (models.SymbolDiff, error) {
+	var diff models.SymbolDiff
+	if len(result) == 0 {
+		// an empty crawl means moneycontrol failed us, not that every company got delisted
+		return diff, errEmptySymbolRefresh
+	}
 	err := s.db.Transaction(func(tx *gorm.DB) error {
-		er := s.db.Exec("delete from company_infos").Error
-		if er != nil {
+		var existing []models.CompanyInfo
+		if er := tx.Select("symbol", "company", "delisted_at").Find(&existing).Error; er != nil {
 			return er
 		}
-		er = s.db.Clauses(clause.OnConflict{
+		stored := make(map[string]models.CompanyInfo, len(existing))
+		for _, company := range existing {
+			stored[company.Symbol] = company
+		}
+
+		crawled := make(map[string]bool, len(result))
+		var rows []models.CompanyInfo
+		for _, company := range result {
+			if crawled[company.Symbol] {
+				continue
+			}
+			crawled[company.Symbol] = true
+			rows = append(rows, company)
+			previous, found := stored[company.Symbol]
+			switch {
+			case !found:
+				diff.Added = append(diff.Added, company.Symbol)
+			case previous.DelistedAt != nil:
+				diff.Relisted = append(diff.Relisted, company.Symbol)
+			}
+			if found && previous.Company != company.Company {
+				diff.Renamed = append(diff.Renamed, models.SymbolRename{
+					Symbol:     company.Symbol,
+					OldCompany: previous.Company,
+					NewCompany: company.Company,
+				})
+			}
+		}
+		var removed []string
+		for _, company := range existing {
+			if !crawled[company.Symbol] && company.DelistedAt == nil {
+				removed = append(removed, company.Symbol)
+			}
+		}
+		diff.Removed = removed
+
+		er := tx.Clauses(clause.OnConflict{
 			Columns:   []clause.Column{{Name: "symbol"}},
-			DoNothing: true,
-		}).Create(&result).Error
-		return er
+			DoUpdates: clause.AssignmentColumns([]string{"company_name", "company", "sector", "delisted_at"}),
+		}).CreateInBatches(&rows, 500).Error
+		if er != nil {
+			return er
+		}
+		if len(removed) == 0 {
+			return nil
+		}
+		return tx.Model(&models.CompanyInfo{}).Where("symbol IN ?", removed).
+			Update("delisted_at", time.Now()).Error
 	})
 	if err != nil {
 		s.vlog.Error(err)
-		return err
+		return diff, err
 	}
-	return err
+	return diff, nil
 }
 
 func (s *moneycontrolRepository) UpdateSymbol(result models.CompanyInfo) error {
@@ -74,3 +175,339 @@ func (s *moneycontrolRepository) FetchCompanyByNameConstant(companyName string)
 	}
 	return &company, nil
 }
+
+func (s *moneycontrolRepository) FetchCompanies() ([]models.CompanyInfo, error) {
+	var companies []models.CompanyInfo
+	err := s.db.Find(&companies).Error
+	if err != nil {
+		s.vlog.Error(err)
+		return nil, err
+	}
+	return companies, nil
+}
+
+// FetchCompaniesToEnrich returns listed companies never enriched or last enriched before staleBefore, never
+// enriched ones first. symbols narrows the result to those moneycontrol symbols or NSE IDs, and a zero
+// staleBefore returns them regardless of when they were enriched.
+func (s *moneycontrolRepository) FetchCompaniesToEnrich(symbols []string, staleBefore time.Time) ([]models.CompanyInfo, error) {
+	var companies []models.CompanyInfo
+	query := s.db.Where("delisted_at IS NULL").Order("enriched_at NULLS FIRST, id")
+	if len(symbols) > 0 {
+		query = query.Where("symbol IN ? OR nse_id IN ?", symbols, symbols)
+	}
+	if !staleBefore.IsZero() {
+		query = query.Where("enriched_at IS NULL OR enriched_at < ?", staleBefore)
+	}
+	err := query.Find(&companies).Error
+	if err != nil {
+		s.vlog.Error(err)
+		return nil, err
+	}
+	return companies, nil
+}
+
+// ResolveCompany looks a company up by NSE ID, BSE scrip code, ISIN, moneycontrol symbol or company name.
+// When several companies match and only one of them is still listed that one wins, otherwise the
+// lookup fails with ErrAmbiguousCompany.
+func (s *moneycontrolRepository) ResolveCompany(identifier string) (*models.CompanyInfo, error) {
+	identifier = strings.TrimSpace(identifier)
+	if identifier == "" {
+		return nil, gorm.ErrRecordNotFound
+	}
+	var companies []models.CompanyInfo
+	upper, lower := strings.ToUpper(identifier), strings.ToLower(identifier)
+	err := s.db.Where("upper(nse_id) = ? OR bse_id = ? OR upper(symbol) = ? OR company = ? OR lower(company_name) = ? OR isin = ?",
+		upper, identifier, upper, lower, lower, upper).Limit(maxResolveCandidates).Find(&companies).Error
+	if err != nil {
+		s.vlog.Error(err)
+		return nil, err
+	}
+	switch len(companies) {
+	case 0:
+		return nil, gorm.ErrRecordNotFound
+	case 1:
+		return &companies[0], nil
+	}
+	var listed []models.CompanyInfo
+	for _, company := range companies {
+		if company.DelistedAt == nil {
+			listed = append(listed, company)
+		}
+	}
+	if len(listed) == 1 {
+		return &listed[0], nil
+	}
+	var candidates []string
+	for _, company := range companies {
+		candidates = append(candidates, fmt.Sprintf("%s (NSE %q, BSE %q)", company.Symbol, company.NSEID, company.BSEID))
+	}
+	return nil, fmt.Errorf("%w: %q matches %s", ErrAmbiguousCompany, identifier, strings.Join(candidates, ", "))
+}
+
+// UpsertDividends stores the dividend history of a company, overwriting rows with the same ex date
+func (s *moneycontrolRepository) UpsertDividends(companyID int64, dividends []models.Dividend) error {
+	if len(dividends) == 0 {
+		return nil
+	}
+	// postgres rejects an upsert that touches the same row twice, so keep the last row per ex date
+	byExDate := make(map[int64]int, len(dividends))
+	var rows []models.Dividend
+	for _, dividend := range dividends {
+		dividend.ID = 0
+		dividend.CompanyID = companyID
+		if idx, found := byExDate[dividend.ExDate]; found {
+			rows[idx] = dividend
+			continue
+		}
+		byExDate[dividend.ExDate] = len(rows)
+		rows = append(rows, dividend)
+	}
+	err := s.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "company_id"}, {Name: "ex_date"}},
+		DoUpdates: clause.AssignmentColumns([]string{"announcement_date", "dividend_type", "dividend_percentage", "dividend", "remark"}),
+	}).CreateInBatches(&rows, 500).Error
+	if err != nil {
+		s.vlog.Error(err)
+		return err
+	}
+	return nil
+}
+
+func (s *moneycontrolRepository) FetchDividends(companyID int64) ([]models.Dividend, error) {
+	var dividends []models.Dividend
+	err := s.db.Where("company_id = ?", companyID).Order("ex_date desc").Find(&dividends).Error
+	if err != nil {
+		s.vlog.Error(err)
+		return nil, err
+	}
+	return dividends, nil
+}
+
+// UpsertCandles stores daily candles of a company, overwriting rows of the same day
+func (s *moneycontrolRepository) UpsertCandles(companyID int64, candles []models.Candle) error {
+	if len(candles) == 0 {
+		return nil
+	}
+	byTimestamp := make(map[int64]int, len(candles))
+	var rows []models.Candle
+	for _, candle := range candles {
+		candle.ID = 0
+		candle.CompanyID = companyID
+		if idx, found := byTimestamp[candle.Timestamp]; found {
+			rows[idx] = candle
+			continue
+		}
+		byTimestamp[candle.Timestamp] = len(rows)
+		rows = append(rows, candle)
+	}
+	err := s.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "company_id"}, {Name: "timestamp"}},
+		DoUpdates: clause.AssignmentColumns([]string{"open", "high", "low", "close", "volume"}),
+	}).CreateInBatches(&rows, 500).Error
+	if err != nil {
+		s.vlog.Error(err)
+		return err
+	}
+	return nil
+}
+
+// FetchCandles returns the candles of a company between from and to (unix seconds, inclusive),
+// a zero bound leaving that side open
+func (s *moneycontrolRepository) FetchCandles(companyID int64, from, to int64) ([]models.Candle, error) {
+	var candles []models.Candle
+	query := s.db.Where("company_id = ?", companyID)
+	if from != 0 {
+		query = query.Where("timestamp >= ?", from)
+	}
+	if to != 0 {
+		query = query.Where("timestamp <= ?", to)
+	}
+	err := query.Order("timestamp").Find(&candles).Error
+	if err != nil {
+		s.vlog.Error(err)
+		return nil, err
+	}
+	return candles, nil
+}
+
+// SaveMarketDepth stores an order book snapshot of a company
+func (s *moneycontrolRepository) SaveMarketDepth(depth *models.MarketDepth) error {
+	err := s.db.Create(depth).Error
+	if err != nil {
+		s.vlog.Error(err)
+		return err
+	}
+	return nil
+}
+
+// FetchMarketDepth returns the newest order book snapshots of a company captured between from and to,
+// zero times leaving that end open
+func (s *moneycontrolRepository) FetchMarketDepth(companyID int64, from, to time.Time, limit int) ([]models.MarketDepth, error) {
+	var snapshots []models.MarketDepth
+	query := s.db.Where("company_id = ?", companyID)
+	if !from.IsZero() {
+		query = query.Where("captured_at >= ?", from)
+	}
+	if !to.IsZero() {
+		query = query.Where("captured_at <= ?", to)
+	}
+	err := query.Order("captured_at desc").Limit(limit).Find(&snapshots).Error
+	if err != nil {
+		s.vlog.Error(err)
+		return nil, err
+	}
+	return snapshots, nil
+}
+
+// FetchExchangeIDs returns the NSE ID of every listed company, or its BSE scrip code when it isn't on
+// NSE, optionally limited to a sector matched case insensitively against Sector or MainSectorDetails
+// and to the constituents of an index
+func (s *moneycontrolRepository) FetchExchangeIDs(sector, index string) ([]string, error) {
+	var exchangeIDs []string
+	query := s.db.Model(&models.CompanyInfo{}).Where("(nse_id <> '' OR bse_id <> '') AND delisted_at IS NULL")
+	if sector != "" {
+		query = query.Where("lower(sector) = ? OR lower(main_sector_details) = ?",
+			strings.ToLower(sector), strings.ToLower(sector))
+	}
+	if index != "" {
+		query = query.Where("id IN (?)", s.indexMembers(index))
+	}
+	err := query.Order("1").Pluck("coalesce(nullif(nse_id, ''), bse_id)", &exchangeIDs).Error
+	if err != nil {
+		s.vlog.Error(err)
+		return nil, err
+	}
+	return exchangeIDs, nil
+}
+
+// FetchLatestCandleTimestamps returns the timestamp of the newest stored candle per company id
+func (s *moneycontrolRepository) FetchLatestCandleTimestamps() (map[int64]int64, error) {
+	var rows []struct {
+		CompanyID int64
+		Latest    int64
+	}
+	err := s.db.Model(&models.Candle{}).Select("company_id, max(timestamp) as latest").
+		Group("company_id").Scan(&rows).Error
+	if err != nil {
+		s.vlog.Error(err)
+		return nil, err
+	}
+	latest := make(map[int64]int64, len(rows))
+	for _, row := range rows {
+		latest[row.CompanyID] = row.Latest
+	}
+	return latest, nil
+}
+
+func (s *moneycontrolRepository) CreateBackfillRun(run *models.BackfillRun) error {
+	err := s.db.Create(run).Error
+	if err != nil {
+		s.vlog.Error(err)
+	}
+	return err
+}
+
+func (s *moneycontrolRepository) SaveBackfillRun(run *models.BackfillRun) error {
+	err := s.db.Save(run).Error
+	if err != nil {
+		s.vlog.Error(err)
+	}
+	return err
+}
+
+func (s *moneycontrolRepository) FetchLatestBackfillRun() (*models.BackfillRun, error) {
+	var run models.BackfillRun
+	err := s.db.Order("id desc").First(&run).Error
+	if err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+// SaveBackfillProgress records the outcome of a company within a run, replacing an earlier attempt
+func (s *moneycontrolRepository) SaveBackfillProgress(progress models.BackfillProgress) error {
+	err := s.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "run_id"}, {Name: "company_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"status", "error", "updated_at"}),
+	}).Create(&progress).Error
+	if err != nil {
+		s.vlog.Error(err)
+	}
+	return err
+}
+
+// FetchBackfillStatuses returns the status of every company a run already went through by company id
+func (s *moneycontrolRepository) FetchBackfillStatuses(runID int64) (map[int64]string, error) {
+	var rows []models.BackfillProgress
+	err := s.db.Select("company_id", "status").Where("run_id = ?", runID).Find(&rows).Error
+	if err != nil {
+		s.vlog.Error(err)
+		return nil, err
+	}
+	statuses := make(map[int64]string, len(rows))
+	for _, row := range rows {
+		statuses[row.CompanyID] = row.Status
+	}
+	return statuses, nil
+}
+
+// SearchCompanies returns one page of companies matching the filter along with the total match count
+func (s *moneycontrolRepository) SearchCompanies(filter models.CompanyFilter) ([]models.CompanyInfo, int64, error) {
+	query := s.db.Model(&models.CompanyInfo{})
+	if !filter.IncludeDelisted {
+		query = query.Where("delisted_at IS NULL")
+	}
+	if filter.Sector != "" {
+		query = query.Where("sector ILIKE ?", sqlutil.EscapeLike(filter.Sector))
+	}
+	if filter.MainSector != "" {
+		query = query.Where("main_sector_details ILIKE ?", sqlutil.EscapeLike(filter.MainSector))
+	}
+	if filter.SubSector != "" {
+		query = query.Where("sub_sector_details ILIKE ?", sqlutil.EscapeLike(filter.SubSector))
+	}
+	for _, word := range strings.Fields(filter.Query) {
+		pattern := "%" + sqlutil.EscapeLike(word) + "%"
+		query = query.Where("company ILIKE ? OR company_name ILIKE ? OR nse_id ILIKE ? OR bse_id ILIKE ? OR isin ILIKE ?",
+			pattern, pattern, pattern, pattern, pattern)
+	}
+	if filter.Index != "" {
+		query = query.Where("id IN (?)", s.indexMembers(filter.Index))
+	}
+	if filter.MinPE != 0 || filter.MaxPE != 0 {
+		query = query.Where("pe > 0")
+	}
+	if filter.MinPE != 0 {
+		query = query.Where("pe >= ?", filter.MinPE)
+	}
+	if filter.MaxPE != 0 {
+		query = query.Where("pe <= ?", filter.MaxPE)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		s.vlog.Error(err)
+		return nil, 0, err
+	}
+
+	switch filter.Sort {
+	case models.SortByMarketCapDesc:
+		query = query.Order("market_cap desc")
+	case models.SortByMarketCapAsc:
+		query = query.Order("market_cap asc")
+	case models.SortByPEAsc:
+		query = query.Order("pe asc")
+	case models.SortByPEDesc:
+		query = query.Order("pe desc")
+	default:
+		query = query.Order("company")
+	}
+	var companies []models.CompanyInfo
+	err := query.Order("id").Offset((filter.Page - 1) * filter.PageSize).Limit(filter.PageSize).
+		Find(&companies).Error
+	if err != nil {
+		s.vlog.Error(err)
+		return nil, 0, err
+	}
+	return companies, total, nil
+}
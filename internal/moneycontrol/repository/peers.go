@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"strings"
+
+	"github.com/johnsonabraham/moneycontrolscraper/internal/moneycontrol/models"
+)
+
+// FetchSubSectorCompanies returns the listed companies of a sub-sector, matched case insensitively,
+// largest market cap first
+func (s *moneycontrolRepository) FetchSubSectorCompanies(subSector string) ([]models.CompanyInfo, error) {
+	var companies []models.CompanyInfo
+	err := s.db.Where("delisted_at IS NULL AND lower(sub_sector_details) = ?", strings.ToLower(subSector)).
+		Order("market_cap desc").Order("id").Find(&companies).Error
+	if err != nil {
+		s.vlog.Error(err)
+		return nil, err
+	}
+	return companies, nil
+}
+
+// FetchLatestCandles returns the newest stored candle of each company, leaving out companies
+// without candles
+func (s *moneycontrolRepository) FetchLatestCandles(companyIDs []int64) (map[int64]models.Candle, error) {
+	latest := make(map[int64]models.Candle, len(companyIDs))
+	if len(companyIDs) == 0 {
+		return latest, nil
+	}
+	var candles []models.Candle
+	err := s.db.Raw(`SELECT DISTINCT ON (company_id) * FROM candles WHERE company_id IN ?
+		ORDER BY company_id, timestamp DESC`, companyIDs).Scan(&candles).Error
+	if err != nil {
+		s.vlog.Error(err)
+		return nil, err
+	}
+	for _, candle := range candles {
+		latest[candle.CompanyID] = candle
+	}
+	return latest, nil
+}
+
+// FetchSectorSummaries counts the listed companies of every sector and totals their market cap,
+// largest sector first
+func (s *moneycontrolRepository) FetchSectorSummaries() ([]models.SectorSummary, error) {
+	var summaries []models.SectorSummary
+	err := s.db.Model(&models.CompanyInfo{}).
+		Select("sector, count(*) AS companies, coalesce(sum(market_cap), 0) AS market_cap").
+		Where("delisted_at IS NULL AND sector <> ''").
+		Group("sector").Order("market_cap desc").Order("sector").
+		Scan(&summaries).Error
+	if err != nil {
+		s.vlog.Error(err)
+		return nil, err
+	}
+	return summaries, nil
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"github.com/johnsonabraham/moneycontrolscraper/internal/moneycontrol/models"
+	"gorm.io/gorm/clause"
+)
+
+// UpsertShareholdings stores the shareholding pattern of a company, overwriting the percentage of
+// a category already stored for the quarter
+func (s *moneycontrolRepository) UpsertShareholdings(companyID int64, holdings []models.Shareholding) error {
+	if len(holdings) == 0 {
+		return nil
+	}
+	type holdingKey struct{ quarter, category string }
+	byKey := make(map[holdingKey]int, len(holdings))
+	var rows []models.Shareholding
+	for _, holding := range holdings {
+		holding.ID = 0
+		holding.CompanyID = companyID
+		key := holdingKey{holding.Quarter, holding.Category}
+		if idx, found := byKey[key]; found {
+			rows[idx] = holding
+			continue
+		}
+		byKey[key] = len(rows)
+		rows = append(rows, holding)
+	}
+	err := s.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "company_id"}, {Name: "quarter"}, {Name: "category"}},
+		DoUpdates: clause.AssignmentColumns([]string{"percentage"}),
+	}).CreateInBatches(&rows, 500).Error
+	if err != nil {
+		s.vlog.Error(err)
+		return err
+	}
+	return nil
+}
+
+// FetchShareholdings returns the stored shareholding pattern of a company, oldest quarter first
+func (s *moneycontrolRepository) FetchShareholdings(companyID int64) ([]models.Shareholding, error) {
+	var holdings []models.Shareholding
+	err := s.db.Where("company_id = ?", companyID).Order("quarter").Order("category").Find(&holdings).Error
+	if err != nil {
+		s.vlog.Error(err)
+		return nil, err
+	}
+	return holdings, nil
+}
@@ -0,0 +1,204 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/johnsonabraham/moneycontrolscraper/internal/moneycontrol/models"
+)
+
+const (
+	defaultAnnouncementLimit = 100
+	maxAnnouncementLimit     = 1000
+
+	// announcementSettleTime is how long after its capture an announcement is assumed committed
+	announcementSettleTime = time.Minute
+)
+
+var (
+	// announcementDate finds a date such as "18-10-2026", "18 Oct 2026" or "Oct 18, 2026" in a listing
+	announcementDate = regexp.MustCompile(`\d{1,2}[-/ ](?:\d{1,2}|[A-Za-z]{3,9})[-/ ,]+\d{4}|[A-Za-z]{3,9} \d{1,2},? \d{4}`)
+
+	announcementDateLayouts = []string{"02-01-2006", "2-1-2006", "02/01/2006", "2 Jan 2006", "2 January 2006",
+		"Jan 2, 2006", "January 2, 2006", "Jan 2 2006", "January 2 2006"}
+)
+
+// ScrapeAnnouncements captures the corporate announcements moneycontrol lists for a company,
+// skipping those already stored
+func (i *moneyControlService) ScrapeAnnouncements(ticker string) error {
+	companyInfo, err := i.moneycontrolRepository.ResolveCompany(ticker)
+	if err != nil {
+		i.mlog.Error("Error fetching provided company", err)
+		return err
+	}
+	pageURL := fmt.Sprintf(i.cfg.MoneyControlAnnouncementsURL, companyInfo.CompanyName, companyInfo.Symbol)
+	response, err := http.Get(pageURL)
+	if err != nil {
+		i.mlog.Error(fmt.Sprintf("Error while scraping announcements for %s", ticker), err)
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("announcements page answered %s", response.Status)
+	}
+
+	doc, err := goquery.NewDocumentFromReader(response.Body)
+	if err != nil {
+		i.mlog.Error(fmt.Sprintf("Error parsing announcements page for %s", ticker), err)
+		return err
+	}
+	announcements := parseAnnouncements(doc, pageURL)
+	now := time.Now()
+	for idx := range announcements {
+		announcements[idx].CompanyID = companyInfo.ID
+		announcements[idx].CapturedAt = now
+		announcements[idx].Hash = announcementHash(announcements[idx])
+	}
+	added, err := i.moneycontrolRepository.InsertAnnouncements(announcements)
+	if err != nil {
+		i.mlog.Error(fmt.Sprintf("Error saving announcements for %s", ticker), err)
+		return err
+	}
+	i.mlog.Info(fmt.Sprintf("Captured %d announcements for %s, %d new", len(announcements), ticker, added))
+	return nil
+}
+
+// parseAnnouncements reads every dated link of an announcements listing. The date and category
+// come from the text around the link, the category being the part that isn't the date when the
+// listing separates them with "|".
+func parseAnnouncements(doc *goquery.Document, pageURL string) []models.Announcement {
+	base, _ := url.Parse(pageURL)
+	var announcements []models.Announcement
+	seen := make(map[string]bool)
+	doc.Find("li, tr, div").Each(func(_ int, block *goquery.Selection) {
+		title, rest, date, found := announcementBlock(block)
+		// only the innermost block holding a single dated link is an announcement
+		if !found || block.Find("li, tr, div").FilterFunction(func(_ int, s *goquery.Selection) bool {
+			_, _, _, nested := announcementBlock(s)
+			return nested
+		}).Length() > 0 {
+			return
+		}
+		href, _ := block.Find("a[href]").Attr("href")
+		if base != nil {
+			if resolved, err := base.Parse(href); err == nil {
+				href = resolved.String()
+			}
+		}
+		if seen[href+title] {
+			return
+		}
+		seen[href+title] = true
+
+		var category string
+		for _, part := range strings.Split(rest, "|") {
+			part = strings.TrimSpace(part)
+			if part != "" && !announcementDate.MatchString(part) {
+				category = part
+				break
+			}
+		}
+		announcements = append(announcements, models.Announcement{
+			Date:     date,
+			Category: category,
+			Title:    title,
+			Link:     href,
+		})
+	})
+	return announcements
+}
+
+// announcementBlock reports whether an element holds exactly one link next to a date, returning the
+// link title, the text around it and the date
+func announcementBlock(block *goquery.Selection) (title, rest string, date time.Time, found bool) {
+	links := block.Find("a[href]")
+	if links.Length() != 1 {
+		return
+	}
+	title = strings.Join(strings.Fields(links.Text()), " ")
+	href, _ := links.Attr("href")
+	if title == "" || href == "" || strings.HasPrefix(href, "javascript") {
+		return
+	}
+	rest = strings.Replace(strings.Join(strings.Fields(block.Text()), " "), title, "", 1)
+	date, found = parseAnnouncementDate(announcementDate.FindString(rest))
+	return
+}
+
+// parseAnnouncementDate reads a listing date as the start of that day in IST
+func parseAnnouncementDate(text string) (time.Time, bool) {
+	text = strings.Join(strings.Fields(strings.ReplaceAll(text, ",", ", ")), " ")
+	text = strings.ReplaceAll(text, " ,", ",")
+	for _, layout := range announcementDateLayouts {
+		if t, err := time.ParseInLocation(layout, text, MarketLocation); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// announcementHash identifies an announcement by its company, date, title and link
+func announcementHash(announcement models.Announcement) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%d|%s|%s|%s", announcement.CompanyID,
+		announcement.Date.Format("2006-01-02"), announcement.Title, announcement.Link)))
+	return hex.EncodeToString(sum[:])
+}
+
+// EnqueueAnnouncementCollection queues a job scraping the announcements of a company
+func (i *moneyControlService) EnqueueAnnouncementCollection(ticker string) (*models.Job, error) {
+	if _, err := i.moneycontrolRepository.ResolveCompany(ticker); err != nil {
+		return nil, err
+	}
+	return i.jobs.Enqueue(models.JobCollectAnnouncements, ticker, func(progress *JobProgress) error {
+		progress.SetTotal(1)
+		err := i.ScrapeAnnouncements(ticker)
+		progress.Step(err)
+		return err
+	})
+}
+
+// GetAnnouncements returns the stored announcements of a company, or of every company when ticker
+// is empty, matching filter. The page cursor carries on from filter.Since when nothing matched.
+func (i *moneyControlService) GetAnnouncements(ticker string, filter models.AnnouncementFilter) (models.AnnouncementPage, error) {
+	if ticker != "" {
+		companyInfo, err := i.moneycontrolRepository.ResolveCompany(ticker)
+		if err != nil {
+			i.mlog.Error("Error fetching provided company", err)
+			return models.AnnouncementPage{}, err
+		}
+		filter.CompanyID = companyInfo.ID
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultAnnouncementLimit
+	}
+	if filter.Limit > maxAnnouncementLimit {
+		filter.Limit = maxAnnouncementLimit
+	}
+	announcements, err := i.moneycontrolRepository.FetchAnnouncements(filter)
+	if err != nil {
+		return models.AnnouncementPage{}, err
+	}
+	// stop before the first announcement that may still have lower ids committing ahead of it
+	settled := time.Now().Add(-announcementSettleTime)
+	for idx, announcement := range announcements {
+		if announcement.CapturedAt.After(settled) {
+			announcements = announcements[:idx]
+			break
+		}
+	}
+	page := models.AnnouncementPage{Announcements: announcements, NextSince: filter.Since}
+	if page.Announcements == nil {
+		page.Announcements = []models.Announcement{}
+	}
+	if len(announcements) > 0 {
+		page.NextSince = announcements[len(announcements)-1].ID
+	}
+	return page, nil
+}
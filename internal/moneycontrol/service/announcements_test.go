@@ -0,0 +1,94 @@
+package service
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/johnsonabraham/moneycontrolscraper/internal/moneycontrol/models"
+)
+
+func TestParseAnnouncementDate(t *testing.T) {
+	october18 := time.Date(2026, 10, 18, 0, 0, 0, 0, MarketLocation)
+	tests := []struct {
+		text   string
+		want   time.Time
+		wantOK bool
+	}{
+		{"18-10-2026", october18, true},
+		{"8-1-2026", time.Date(2026, 1, 8, 0, 0, 0, 0, MarketLocation), true},
+		{"18/10/2026", october18, true},
+		{"18 Oct 2026", october18, true},
+		{"18 October 2026", october18, true},
+		{"Oct 18, 2026", october18, true},
+		{"Oct 18,2026", october18, true},
+		{"October  18 , 2026", october18, true},
+		{"Oct 18 2026", october18, true},
+		{"2026-10-18", time.Time{}, false},
+		{"31-02-2026", time.Time{}, false},
+		{"", time.Time{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseAnnouncementDate(tt.text)
+		if ok != tt.wantOK || !got.Equal(tt.want) {
+			t.Errorf("parseAnnouncementDate(%q) = %s, %v, want %s, %v", tt.text, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestParseAnnouncements(t *testing.T) {
+	const pageURL = "https://www.moneycontrol.com/stocks/company_info/stock_news.php?sc_id=TCS&durationType=Y"
+	tests := []struct {
+		html string
+		want []models.Announcement
+	}{
+		{
+			`<div class="announcements">
+			  <ul>
+			    <li><a href="/news/business/board-meeting-12345.html">Board Meeting on Oct 24</a><p>18 Oct 2026 | Board Meeting</p></li>
+			    <li><a href="https://www.bseindia.com/xml-data/corpfiling/6789.pdf">Outcome of Board Meeting</a> <span>Oct 16, 2026</span></li>
+			    <li><a href="/news/business/board-meeting-12345.html">Board Meeting on Oct 24</a><p>18 Oct 2026 | Board Meeting</p></li>
+			    <li><a href="javascript:void(0)">Load more</a><p>18 Oct 2026</p></li>
+			    <li><a href="/news/business/undated.html">Undated news</a></li>
+			  </ul>
+			</div>`,
+			[]models.Announcement{
+				{
+					Date:     time.Date(2026, 10, 18, 0, 0, 0, 0, MarketLocation),
+					Category: "Board Meeting",
+					Title:    "Board Meeting on Oct 24",
+					Link:     "https://www.moneycontrol.com/news/business/board-meeting-12345.html",
+				},
+				{
+					Date:  time.Date(2026, 10, 16, 0, 0, 0, 0, MarketLocation),
+					Title: "Outcome of Board Meeting",
+					Link:  "https://www.bseindia.com/xml-data/corpfiling/6789.pdf",
+				},
+			},
+		},
+		{
+			`<table>
+			  <tr><td>18-10-2026</td><td><a href="ann-1.html">Trading Window Closure</a></td></tr>
+			</table>`,
+			[]models.Announcement{
+				{
+					Date:  time.Date(2026, 10, 18, 0, 0, 0, 0, MarketLocation),
+					Title: "Trading Window Closure",
+					Link:  "https://www.moneycontrol.com/stocks/company_info/ann-1.html",
+				},
+			},
+		},
+		{`<ul><li>No announcements</li></ul>`, nil},
+	}
+	for _, tt := range tests {
+		doc, err := goquery.NewDocumentFromReader(strings.NewReader(tt.html))
+		if err != nil {
+			t.Fatalf("invalid fixture: %v", err)
+		}
+		if got := parseAnnouncements(doc, pageURL); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseAnnouncements(%s) = %+v, want %+v", tt.html, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/johnsonabraham/moneycontrolscraper/internal/moneycontrol/models"
+	"gorm.io/gorm"
+)
+
+var (
+	// MarketLocation is the Indian Standard Time zone NSE trades and timestamps its candles in
+	MarketLocation = time.FixedZone("IST", 5*60*60+30*60)
+
+	// ErrBackfillRunning is returned when a backfill is started while another one is in progress
+	ErrBackfillRunning = errors.New("historical data backfill already running")
+)
+
+// backfillState guards against running two backfills in the same process
+type backfillState struct {
+	mu      sync.Mutex
+	running bool
+}
+
+// backfillPlan is a backfill run with the companies it still has to go through
+type backfillPlan struct {
+	run          *models.BackfillRun
+	tickers      []string
+	byExchangeID map[string]models.CompanyInfo
+}
+
+// EnqueueBackfill queues a job fetching historical data for every listed company with an NSE or BSE
+// ID, resuming the last run if the process stopped before it finished
+func (i *moneyControlService) EnqueueBackfill() (*models.Job, error) {
+	plan, err := i.planBackfill()
+	if err != nil {
+		return nil, err
+	}
+	job, err := i.jobs.Enqueue(models.JobBackfillHistoricalData, strconv.FormatInt(plan.run.ID, 10), func(progress *JobProgress) error {
+		i.runBackfill(plan, progress)
+		return nil
+	})
+	if err != nil {
+		i.backfillDone()
+		return nil, err
+	}
+	return job, nil
+}
+
+// planBackfill marks a backfill as running and picks the run and companies it goes through
+func (i *moneyControlService) planBackfill() (plan *backfillPlan, err error) {
+	i.backfill.mu.Lock()
+	defer i.backfill.mu.Unlock()
+	if i.backfill.running {
+		return nil, ErrBackfillRunning
+	}
+
+	companies, err := i.moneycontrolRepository.FetchCompanies()
+	if err != nil {
+		return nil, err
+	}
+	byExchangeID := make(map[string]models.CompanyInfo)
+	var tickers []string
+	for _, company := range companies {
+		id := exchangeID(company)
+		if id == "" || company.DelistedAt != nil {
+			continue
+		}
+		if _, found := byExchangeID[id]; !found {
+			tickers = append(tickers, id)
+		}
+		byExchangeID[id] = company
+	}
+
+	run, err := i.moneycontrolRepository.FetchLatestBackfillRun()
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if run == nil || run.FinishedAt != nil {
+		run = &models.BackfillRun{StartedAt: time.Now(), Total: len(tickers)}
+		if err := i.moneycontrolRepository.CreateBackfillRun(run); err != nil {
+			return nil, err
+		}
+	} else {
+		statuses, err := i.moneycontrolRepository.FetchBackfillStatuses(run.ID)
+		if err != nil {
+			return nil, err
+		}
+		// companies that failed are retried, their earlier failure no longer counting
+		pending := tickers[:0]
+		for _, ticker := range tickers {
+			status, found := statuses[byExchangeID[ticker].ID]
+			if !found || status == models.BackfillFailed {
+				pending = append(pending, ticker)
+			}
+			if status == models.BackfillFailed && run.Failed > 0 {
+				run.Failed--
+			}
+		}
+		tickers = pending
+		if err := i.moneycontrolRepository.SaveBackfillRun(run); err != nil {
+			return nil, err
+		}
+		i.mlog.Info(fmt.Sprintf("Resuming historical data backfill run %d with %d companies left", run.ID, len(tickers)))
+	}
+
+	i.backfill.running = true
+	return &backfillPlan{run: run, tickers: tickers, byExchangeID: byExchangeID}, nil
+}
+
+func (i *moneyControlService) backfillDone() {
+	i.backfill.mu.Lock()
+	defer i.backfill.mu.Unlock()
+	i.backfill.running = false
+}
+
+// ResumeBackfill restarts an unfinished backfill run left by a previous process
+func (i *moneyControlService) ResumeBackfill() {
+	run, err := i.moneycontrolRepository.FetchLatestBackfillRun()
+	if err != nil || run.FinishedAt != nil {
+		return
+	}
+	if _, err := i.EnqueueBackfill(); err != nil {
+		i.mlog.Error("Error resuming historical data backfill", err)
+	}
+}
+
+// GetBackfillStatus returns the latest backfill run
+func (i *moneyControlService) GetBackfillStatus() (*models.BackfillRun, error) {
+	return i.moneycontrolRepository.FetchLatestBackfillRun()
+}
+
+// runBackfill goes through the companies of a plan, reporting each one to progress
+func (i *moneyControlService) runBackfill(plan *backfillPlan, progress *JobProgress) {
+	defer i.backfillDone()
+	run, tickers, byExchangeID := plan.run, plan.tickers, plan.byExchangeID
+	progress.SetTotal(len(tickers))
+
+	latest, err := i.moneycontrolRepository.FetchLatestCandleTimestamps()
+	if err != nil {
+		i.mlog.Error("Error reading latest candles, backfilling every company", err)
+		latest = map[int64]int64{}
+	}
+	upToDate := i.calendar.LastTradingDay(time.Now())
+
+	var mu sync.Mutex
+	record := func(companyID int64, status string, err error) {
+		entry := models.BackfillProgress{RunID: run.ID, CompanyID: companyID, Status: status, UpdatedAt: time.Now()}
+		if err != nil {
+			entry.Error = err.Error()
+		}
+		progress.Step(err)
+		mu.Lock()
+		defer mu.Unlock()
+		switch status {
+		case models.BackfillSucceeded:
+			run.Succeeded++
+		case models.BackfillSkipped:
+			run.Skipped++
+		default:
+			run.Failed++
+		}
+		// a lost progress row makes a resumed run redo the company, a lost run row only its counters
+		if err := i.moneycontrolRepository.SaveBackfillProgress(entry); err != nil {
+			i.mlog.Error(fmt.Sprintf("Error recording backfill progress of company %d", companyID), err)
+		}
+		if err := i.moneycontrolRepository.SaveBackfillRun(run); err != nil {
+			i.mlog.Error(fmt.Sprintf("Error updating historical data backfill run %d", run.ID), err)
+		}
+	}
+
+	i.mlog.Info(fmt.Sprintf("Historical data backfill run %d started for %d companies", run.ID, len(tickers)))
+	i.runBulk(tickers, i.cfg.BackfillWorkers, i.cfg.BackfillRequestInterval, func(ticker string) error {
+		company := byExchangeID[ticker]
+		if ts, found := latest[company.ID]; found && !time.Unix(ts, 0).Before(upToDate) {
+			record(company.ID, models.BackfillSkipped, nil)
+			return nil
+		}
+		err := i.CaptureHistoricalData(ticker)
+		if err != nil {
+			record(company.ID, models.BackfillFailed, err)
+			return err
+		}
+		record(company.ID, models.BackfillSucceeded, nil)
+		return nil
+	})
+
+	finishedAt := time.Now()
+	run.FinishedAt = &finishedAt
+	if err := i.moneycontrolRepository.SaveBackfillRun(run); err != nil {
+		i.mlog.Error("Error finishing historical data backfill run", err)
+	}
+	i.mlog.Info(fmt.Sprintf("Historical data backfill run %d done, %d succeeded, %d skipped, %d failed",
+		run.ID, run.Succeeded, run.Skipped, run.Failed))
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/johnsonabraham/moneycontrolscraper/internal/moneycontrol/models"
+)
+
+// ErrEmptySelection is returned when a bulk collection selects no companies
+var ErrEmptySelection = errors.New("no companies selected")
+
+// EnqueueDividendsBulk queues a job scraping the dividend history of every selected company,
+// the per ticker report becoming the job result
+func (i *moneyControlService) EnqueueDividendsBulk(selection models.BulkSelection) (*models.Job, error) {
+	tickers, err := i.selectTickers(selection)
+	if err != nil {
+		return nil, err
+	}
+	return i.jobs.Enqueue(models.JobCollectDividendsBulk, strings.Join(tickers, ","), func(progress *JobProgress) error {
+		i.mlog.Info(fmt.Sprintf("Bulk dividend collection started for %d companies", len(tickers)))
+		progress.SetTotal(len(tickers))
+		report := i.runBulk(tickers, i.cfg.BulkWorkers, i.cfg.BulkRequestInterval, func(ticker string) error {
+			err := i.ScrapeDividendHistory(ticker)
+			progress.Step(err)
+			return err
+		})
+		progress.SetResult(report)
+		i.mlog.Info(fmt.Sprintf("Bulk dividend collection done, %d succeeded, %d failed", report.Succeeded, report.Failed))
+		return nil
+	})
+}
+
+// selectTickers resolves a bulk selection to the exchange ids to collect
+func (i *moneyControlService) selectTickers(selection models.BulkSelection) ([]string, error) {
+	var tickers []string
+	switch {
+	case len(selection.Tickers) > 0:
+		seen := make(map[string]bool, len(selection.Tickers))
+		for _, ticker := range selection.Tickers {
+			ticker = strings.TrimSpace(ticker)
+			if ticker != "" && !seen[ticker] {
+				seen[ticker] = true
+				tickers = append(tickers, ticker)
+			}
+		}
+	case selection.Sector != "" || selection.Index != "" || selection.All:
+		var err error
+		tickers, err = i.moneycontrolRepository.FetchExchangeIDs(selection.Sector, selection.Index)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(tickers) == 0 {
+		return nil, ErrEmptySelection
+	}
+	return tickers, nil
+}
+
+// runBulk calls collect for every ticker on the given number of workers, starting at most one call
+// per interval so moneycontrol isn't hammered
+func (i *moneyControlService) runBulk(tickers []string, workers int, interval time.Duration, collect func(ticker string) error) models.BulkReport {
+	if workers < 1 {
+		workers = 1
+	}
+	var throttle <-chan time.Time
+	if interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		throttle = ticker.C
+	}
+
+	results := make([]models.BulkResult, len(tickers))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range jobs {
+				if throttle != nil {
+					<-throttle
+				}
+				result := models.BulkResult{Ticker: tickers[idx], Status: "success"}
+				if err := collect(tickers[idx]); err != nil {
+					result.Status = "failed"
+					result.Error = err.Error()
+				}
+				results[idx] = result
+			}
+		}()
+	}
+	for idx := range tickers {
+		jobs <- idx
+	}
+	close(jobs)
+	wg.Wait()
+
+	report := models.BulkReport{Total: len(results), Results: results}
+	for _, result := range results {
+		if result.Error == "" {
+			report.Succeeded++
+		} else {
+			report.Failed++
+		}
+	}
+	return report
+}
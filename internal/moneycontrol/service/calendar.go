@@ -0,0 +1,51 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MarketCalendar knows the days NSE trades on, every weekday but the holidays listed in
+// MARKET_HOLIDAYS
+type MarketCalendar struct {
+	holidays map[string]bool
+}
+
+// NewMarketCalendar reads MARKET_HOLIDAYS, a comma separated list of YYYY-MM-DD dates
+func NewMarketCalendar(spec string) (*MarketCalendar, error) {
+	calendar := &MarketCalendar{holidays: make(map[string]bool)}
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", entry); err != nil {
+			return nil, fmt.Errorf("market holiday %q must be a YYYY-MM-DD date", entry)
+		}
+		calendar.holidays[entry] = true
+	}
+	return calendar, nil
+}
+
+// IsTradingDay reports whether NSE trades on the day of t in IST
+func (c *MarketCalendar) IsTradingDay(t time.Time) bool {
+	t = t.In(MarketLocation)
+	if t.Weekday() == time.Saturday || t.Weekday() == time.Sunday {
+		return false
+	}
+	return !c.holidays[t.Format("2006-01-02")]
+}
+
+// LastTradingDay returns the start of the latest NSE session that has closed by now
+func (c *MarketCalendar) LastTradingDay(now time.Time) time.Time {
+	now = now.In(MarketLocation)
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, MarketLocation)
+	if now.Before(day.Add(15*time.Hour + 30*time.Minute)) {
+		day = day.AddDate(0, 0, -1)
+	}
+	for !c.IsTradingDay(day) {
+		day = day.AddDate(0, 0, -1)
+	}
+	return day
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/johnsonabraham/moneycontrolscraper/internal/moneycontrol/models"
+)
+
+const (
+	defaultCompanyPageSize = 50
+	maxCompanyPageSize     = 500
+)
+
+// ErrInvalidSort is returned when a company listing asks for an unknown sort order
+var ErrInvalidSort = errors.New("invalid sort")
+
+// SearchCompanies returns one page of the company directory
+func (i *moneyControlService) SearchCompanies(filter models.CompanyFilter) (models.CompanyPage, error) {
+	switch filter.Sort {
+	case "":
+		filter.Sort = models.SortByName
+	case models.SortByName, models.SortByMarketCapDesc, models.SortByMarketCapAsc,
+		models.SortByPEAsc, models.SortByPEDesc:
+	default:
+		return models.CompanyPage{}, ErrInvalidSort
+	}
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.PageSize < 1 {
+		filter.PageSize = defaultCompanyPageSize
+	}
+	if filter.PageSize > maxCompanyPageSize {
+		filter.PageSize = maxCompanyPageSize
+	}
+	companies, total, err := i.moneycontrolRepository.SearchCompanies(filter)
+	if err != nil {
+		return models.CompanyPage{}, err
+	}
+	if companies == nil {
+		companies = []models.CompanyInfo{}
+	}
+	return models.CompanyPage{
+		Total:     total,
+		Page:      filter.Page,
+		PageSize:  filter.PageSize,
+		Companies: companies,
+	}, nil
+}
+
+// GetCompany returns the company an NSE ID, BSE scrip code, symbol, name or ISIN identifies
+func (i *moneyControlService) GetCompany(ticker string) (*models.CompanyInfo, error) {
+	return i.moneycontrolRepository.ResolveCompany(ticker)
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/johnsonabraham/moneycontrolscraper/internal/moneycontrol/models"
+)
+
+// detailKeys lists the keys of the company details data object each typed field is read from,
+// first match winning. Keys are compared case insensitively.
+var detailKeys = struct {
+	week52High, week52Low, pe, industryPE, bookValue, faceValue, isin []string
+}{
+	week52High: []string{"52H", "HP52", "52WeekHigh"},
+	week52Low:  []string{"52L", "LP52", "52WeekLow"},
+	pe:         []string{"PE", "PERATIO"},
+	industryPE: []string{"IND_PE", "INDPE", "IndustryPE"},
+	bookValue:  []string{"BV", "BOOKVALUE", "BVPS"},
+	faceValue:  []string{"FV", "FACEVALUE"},
+	isin:       []string{"ISIN", "isinid"},
+}
+
+// applyDetailFields copies the 52 week range, valuation ratios and ISIN of a company details
+// response into the company. Missing or non numeric values such as "-" leave the field at zero.
+func applyDetailFields(companyInfo *models.CompanyInfo, body []byte) {
+	var payload struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return
+	}
+	fields := make(map[string]interface{}, len(payload.Data))
+	for key, value := range payload.Data {
+		fields[strings.ToLower(key)] = value
+	}
+	companyInfo.Week52High = detailNumber(fields, detailKeys.week52High)
+	companyInfo.Week52Low = detailNumber(fields, detailKeys.week52Low)
+	companyInfo.PE = detailNumber(fields, detailKeys.pe)
+	companyInfo.IndustryPE = detailNumber(fields, detailKeys.industryPE)
+	companyInfo.BookValue = detailNumber(fields, detailKeys.bookValue)
+	companyInfo.FaceValue = detailNumber(fields, detailKeys.faceValue)
+	companyInfo.ISIN = detailString(fields, detailKeys.isin)
+}
+
+func detailNumber(fields map[string]interface{}, keys []string) float64 {
+	for _, key := range keys {
+		if number, ok := jsonNumber(fields[strings.ToLower(key)]); ok {
+			return number
+		}
+	}
+	return 0
+}
+
+func detailString(fields map[string]interface{}, keys []string) string {
+	for _, key := range keys {
+		if value, ok := fields[strings.ToLower(key)].(string); ok && strings.TrimSpace(value) != "" {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/johnsonabraham/moneycontrolscraper/internal/moneycontrol/models"
+)
+
+var (
+	// corporateActionPages maps each corporate action to its company facts page
+	corporateActionPages = map[string]string{
+		models.CorporateActionBonus:  "bonus",
+		models.CorporateActionSplit:  "splits",
+		models.CorporateActionRights: "rights",
+	}
+
+	// ErrInvalidActionType is returned when corporate actions are requested for an unknown type
+	ErrInvalidActionType = errors.New("invalid corporate action type")
+)
+
+// ScrapeCorporateActions captures the bonus issues, splits and rights issues of a company.
+// A page failing doesn't stop the others, the errors being returned together.
+func (i *moneyControlService) ScrapeCorporateActions(ticker string) error {
+	companyInfo, err := i.moneycontrolRepository.ResolveCompany(ticker)
+	if err != nil {
+		i.mlog.Error("Error fetching provided company", err)
+		return err
+	}
+	var actions []models.CorporateAction
+	var errs []error
+	for _, actionType := range models.CorporateActionTypes {
+		scraped, err := i.scrapeCorporateActionPage(*companyInfo, actionType)
+		if err != nil {
+			i.mlog.Error(fmt.Sprintf("Error scraping %s history for %s", actionType, ticker), err)
+			errs = append(errs, fmt.Errorf("%s: %w", actionType, err))
+			continue
+		}
+		actions = append(actions, scraped...)
+	}
+	if err := i.moneycontrolRepository.UpsertCorporateActions(companyInfo.ID, actions); err != nil {
+		i.mlog.Error(fmt.Sprintf("Error saving corporate actions for %s", ticker), err)
+		return err
+	}
+	return errors.Join(errs...)
+}
+
+func (i *moneyControlService) scrapeCorporateActionPage(companyInfo models.CompanyInfo, actionType string) ([]models.CorporateAction, error) {
+	url := fmt.Sprintf(i.cfg.MoneyControlCorporateActionsURL, companyInfo.CompanyName, corporateActionPages[actionType], companyInfo.Symbol)
+	response, err := http.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("company facts page answered %s", response.Status)
+	}
+
+	doc, err := goquery.NewDocumentFromReader(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	return parseCorporateActions(doc, actionType), nil
+}
+
+// corporateActionColumn names the column a table heading holds
+func corporateActionColumn(heading string) string {
+	heading = strings.ToLower(strings.Join(strings.Fields(heading), " "))
+	switch {
+	case strings.Contains(heading, "announce"):
+		return "announcement"
+	case strings.Contains(heading, "record"):
+		return "record"
+	case strings.HasPrefix(heading, "ex"), strings.Contains(heading, "split date"):
+		return "ex"
+	case strings.Contains(heading, "ratio"):
+		return "ratio"
+	case strings.Contains(heading, "old"):
+		return "old"
+	case strings.Contains(heading, "new"):
+		return "new"
+	case strings.Contains(heading, "premium"):
+		return "premium"
+	case strings.Contains(heading, "remark"):
+		return "remark"
+	}
+	return ""
+}
+
+// parseCorporateActions reads a company facts table, locating columns by their headings since the
+// bonus, splits and rights pages lay them out differently. Rows without an ex or record date are
+// skipped as nothing keys them.
+func parseCorporateActions(doc *goquery.Document, actionType string) []models.CorporateAction {
+	headings := doc.Find("table.mctable1 th")
+	if headings.Length() == 0 {
+		// some pages put the headings in the first row
+		headings = doc.Find("table.mctable1 tr").First().Find("td")
+	}
+	columns := make(map[string]int)
+	headings.Each(func(idx int, s *goquery.Selection) {
+		if column := corporateActionColumn(s.Text()); column != "" {
+			if _, found := columns[column]; !found {
+				columns[column] = idx
+			}
+		}
+	})
+
+	var actions []models.CorporateAction
+	doc.Find("table.mctable1>tbody>tr").Each(func(_ int, s *goquery.Selection) {
+		cells := s.Find("td")
+		cell := func(column string) string {
+			idx, found := columns[column]
+			if !found || idx >= cells.Length() {
+				return ""
+			}
+			return strings.TrimSpace(cells.Eq(idx).Text())
+		}
+		action := models.CorporateAction{
+			Type:             actionType,
+			AnnouncementDate: corporateActionDate(cell("announcement")),
+			RecordDate:       corporateActionDate(cell("record")),
+			ExDate:           corporateActionDate(cell("ex")),
+			Ratio:            cell("ratio"),
+			Remark:           cell("remark"),
+		}
+		if action.ExDate == 0 {
+			action.ExDate = action.RecordDate
+		}
+		if action.ExDate == 0 {
+			return
+		}
+		action.OldFaceValue, _ = jsonNumber(cell("old"))
+		action.NewFaceValue, _ = jsonNumber(cell("new"))
+		action.Premium, _ = jsonNumber(cell("premium"))
+		action.AdjustmentFactor = adjustmentFactor(action)
+		actions = append(actions, action)
+	})
+	return actions
+}
+
+// corporateActionDate reads a DD-MM-YYYY date as the unix time of the start of that day in IST,
+// 0 when the cell is empty or malformed
+func corporateActionDate(text string) int64 {
+	t, err := time.ParseInLocation("02-01-2006", text, MarketLocation)
+	if err != nil {
+		return 0
+	}
+	return t.Unix()
+}
+
+// adjustmentFactor derives the price adjustment of a bonus issue from its new:held ratio and of a
+// split from its old and new face values
+func adjustmentFactor(action models.CorporateAction) float64 {
+	switch action.Type {
+	case models.CorporateActionBonus:
+		issued, held, found := strings.Cut(action.Ratio, ":")
+		if !found {
+			return 0
+		}
+		newShares, err := strconv.ParseFloat(strings.TrimSpace(issued), 64)
+		if err != nil {
+			return 0
+		}
+		heldShares, err := strconv.ParseFloat(strings.TrimSpace(held), 64)
+		if err != nil || heldShares == 0 {
+			return 0
+		}
+		return (newShares + heldShares) / heldShares
+	case models.CorporateActionSplit:
+		if action.NewFaceValue == 0 {
+			return 0
+		}
+		return action.OldFaceValue / action.NewFaceValue
+	}
+	return 0
+}
+
+// EnqueueCorporateActionsCollection queues a job scraping the corporate actions of a company
+func (i *moneyControlService) EnqueueCorporateActionsCollection(ticker string) (*models.Job, error) {
+	if _, err := i.moneycontrolRepository.ResolveCompany(ticker); err != nil {
+		return nil, err
+	}
+	return i.jobs.Enqueue(models.JobCollectCorporateActions, ticker, func(progress *JobProgress) error {
+		progress.SetTotal(1)
+		err := i.ScrapeCorporateActions(ticker)
+		progress.Step(err)
+		return err
+	})
+}
+
+// GetCorporateActions returns the stored corporate actions of a company, optionally of one type
+func (i *moneyControlService) GetCorporateActions(ticker, actionType string) ([]models.CorporateAction, error) {
+	if _, found := corporateActionPages[actionType]; actionType != "" && !found {
+		return nil, ErrInvalidActionType
+	}
+	companyInfo, err := i.moneycontrolRepository.ResolveCompany(ticker)
+	if err != nil {
+		i.mlog.Error("Error fetching provided company", err)
+		return nil, err
+	}
+	return i.moneycontrolRepository.FetchCorporateActions(companyInfo.ID, actionType)
+}
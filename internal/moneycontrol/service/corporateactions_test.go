@@ -0,0 +1,30 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/johnsonabraham/moneycontrolscraper/internal/moneycontrol/models"
+)
+
+func TestAdjustmentFactor(t *testing.T) {
+	tests := []struct {
+		action models.CorporateAction
+		want   float64
+	}{
+		{models.CorporateAction{Type: models.CorporateActionBonus, Ratio: "1:1"}, 2},
+		{models.CorporateAction{Type: models.CorporateActionBonus, Ratio: " 3 : 2 "}, 2.5},
+		{models.CorporateAction{Type: models.CorporateActionBonus, Ratio: "1:0"}, 0},
+		{models.CorporateAction{Type: models.CorporateActionBonus, Ratio: "1-1"}, 0},
+		{models.CorporateAction{Type: models.CorporateActionBonus, Ratio: "one:1"}, 0},
+		{models.CorporateAction{Type: models.CorporateActionBonus}, 0},
+		{models.CorporateAction{Type: models.CorporateActionSplit, OldFaceValue: 10, NewFaceValue: 2}, 5},
+		{models.CorporateAction{Type: models.CorporateActionSplit, OldFaceValue: 10, NewFaceValue: 1}, 10},
+		{models.CorporateAction{Type: models.CorporateActionSplit, OldFaceValue: 10}, 0},
+		{models.CorporateAction{Type: models.CorporateActionRights, Ratio: "1:5", Premium: 90}, 0},
+	}
+	for _, tt := range tests {
+		if got := adjustmentFactor(tt.action); got != tt.want {
+			t.Errorf("adjustmentFactor(%+v) = %v, want %v", tt.action, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/johnsonabraham/moneycontrolscraper/internal/moneycontrol/models"
+)
+
+var (
+	// statementPages maps each statement to its page under the moneycontrol financials URL
+	statementPages = map[string]string{
+		models.StatementQuarterlyResults: "results/quarterly-results",
+		models.StatementProfitLoss:       "profit-lossVI",
+		models.StatementBalanceSheet:     "balance-sheetVI",
+		models.StatementCashFlow:         "cash-flowVI",
+	}
+
+	// ErrInvalidStatement is returned when financials are requested for an unknown statement
+	ErrInvalidStatement = errors.New("invalid statement")
+
+	// ErrInvalidPeriod is returned when financials are requested for a period not in YYYY-MM format
+	ErrInvalidPeriod = errors.New("invalid period")
+)
+
+// periodFormat is the layout financial periods are stored in
+const periodFormat = "2006-01"
+
+// ScrapeFinancials captures the quarterly results, profit and loss, balance sheet and cash flow
+// statements of a company. moneycontrol shows the latest five periods of each statement. A statement
+// failing doesn't stop the others, the errors being returned together.
+func (i *moneyControlService) ScrapeFinancials(ticker string) error {
+	companyInfo, err := i.moneycontrolRepository.ResolveCompany(ticker)
+	if err != nil {
+		i.mlog.Error("Error fetching provided company", err)
+		return err
+	}
+	var errs []error
+	for _, statement := range models.Statements {
+		if err := i.scrapeStatement(*companyInfo, statement); err != nil {
+			i.mlog.Error(fmt.Sprintf("Error scraping %s of %s", statement, ticker), err)
+			errs = append(errs, fmt.Errorf("%s: %w", statement, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (i *moneyControlService) scrapeStatement(companyInfo models.CompanyInfo, statement string) error {
+	url := fmt.Sprintf(i.cfg.MoneyControlFinancialsURL, companyInfo.CompanyName, statementPages[statement], companyInfo.Symbol)
+	response, err := http.Get(url)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("financials page answered %s", response.Status)
+	}
+
+	doc, err := goquery.NewDocumentFromReader(response.Body)
+	if err != nil {
+		return err
+	}
+	items, err := parseStatement(doc, statement)
+	if err != nil {
+		return err
+	}
+	return i.moneycontrolRepository.UpsertFinancials(companyInfo.ID, items)
+}
+
+// parseStatement reads the line items of a financials page. The first row naming periods is the
+// header, rows without any figure are section headings and "--" cells become nil values.
+func parseStatement(doc *goquery.Document, statement string) ([]models.FinancialLineItem, error) {
+	var periods []string
+	var items []models.FinancialLineItem
+	var section string
+	seen := make(map[string]int)
+	position := 0
+	now := time.Now()
+	doc.Find("table.mctable1 tr").Each(func(_ int, row *goquery.Selection) {
+		cells := row.Find("td")
+		if cells.Length() < 2 {
+			return
+		}
+		label := strings.Join(strings.Fields(cells.First().Text()), " ")
+		values := cells.Slice(1, goquery.ToEnd)
+
+		if periods == nil {
+			header := make([]string, values.Length())
+			found := false
+			values.Each(func(idx int, cell *goquery.Selection) {
+				if period, ok := parsePeriod(cell.Text()); ok {
+					header[idx] = period
+					found = true
+				}
+			})
+			if found {
+				periods = header
+			}
+			return
+		}
+		if label == "" {
+			return
+		}
+
+		texts := make([]string, 0, values.Length())
+		blank := true
+		values.Each(func(_ int, cell *goquery.Selection) {
+			text := strings.TrimSpace(cell.Text())
+			texts = append(texts, text)
+			if text != "" {
+				blank = false
+			}
+		})
+		if blank {
+			section = label
+			return
+		}
+
+		// some statements repeat a label in different sections
+		seen[label]++
+		lineItem := label
+		if seen[label] > 1 {
+			lineItem = fmt.Sprintf("%s (%d)", label, seen[label])
+		}
+		position++
+		for idx, text := range texts {
+			if idx >= len(periods) || periods[idx] == "" {
+				continue
+			}
+			item := models.FinancialLineItem{
+				Statement: statement,
+				Period:    periods[idx],
+				LineItem:  lineItem,
+				Section:   section,
+				Position:  position,
+				UpdatedAt: now,
+			}
+			if value, ok := jsonNumber(text); ok {
+				item.Value = &value
+			}
+			items = append(items, item)
+		}
+	})
+	if periods == nil {
+		return nil, fmt.Errorf("no %s table found", statement)
+	}
+	return items, nil
+}
+
+// parsePeriod reads a statement column heading such as "Mar '24" or "Mar 2024" into YYYY-MM
+func parsePeriod(text string) (string, bool) {
+	text = strings.Join(strings.Fields(strings.ReplaceAll(text, "'", " ")), " ")
+	for _, layout := range []string{"Jan 06", "Jan 2006"} {
+		if t, err := time.Parse(layout, text); err == nil {
+			return t.Format(periodFormat), true
+		}
+	}
+	return "", false
+}
+
+// EnqueueFinancialsCollection queues a job scraping the financial statements of a company
+func (i *moneyControlService) EnqueueFinancialsCollection(ticker string) (*models.Job, error) {
+	if _, err := i.moneycontrolRepository.ResolveCompany(ticker); err != nil {
+		return nil, err
+	}
+	return i.jobs.Enqueue(models.JobCollectFinancials, ticker, func(progress *JobProgress) error {
+		progress.SetTotal(1)
+		err := i.ScrapeFinancials(ticker)
+		progress.Step(err)
+		return err
+	})
+}
+
+// GetFinancials returns the stored financial statements of a company, optionally limited to one
+// statement and one YYYY-MM period
+func (i *moneyControlService) GetFinancials(ticker, statement, period string) ([]models.FinancialLineItem, error) {
+	if _, found := statementPages[statement]; statement != "" && !found {
+		return nil, ErrInvalidStatement
+	}
+	if period != "" {
+		if _, err := time.Parse(periodFormat, period); err != nil {
+			return nil, ErrInvalidPeriod
+		}
+	}
+	companyInfo, err := i.moneycontrolRepository.ResolveCompany(ticker)
+	if err != nil {
+		i.mlog.Error("Error fetching provided company", err)
+		return nil, err
+	}
+	return i.moneycontrolRepository.FetchFinancials(companyInfo.ID, statement, period)
+}
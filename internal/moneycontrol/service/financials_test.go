@@ -0,0 +1,95 @@
+package service
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func TestParseStatement(t *testing.T) {
+	tests := []struct {
+		html    string
+		want    []string // section|line item|period|position|value
+		wantErr bool
+	}{
+		{
+			`<table class="mctable1">
+			  <tr><td>Balance Sheet of TCS (in Rs. Cr.)</td><td>Mar '24</td><td>Mar '23</td><td>12 mths</td></tr>
+			  <tr><td>EQUITIES AND LIABILITIES</td><td></td><td></td><td></td></tr>
+			  <tr><td>Equity Share Capital</td><td>362.00</td><td>366.00</td><td>x</td></tr>
+			  <tr><td>Reserves and   Surplus</td><td>1,00,000.50</td><td>--</td><td></td></tr>
+			  <tr><td>ASSETS</td><td></td><td></td><td></td></tr>
+			  <tr><td>Equity Share Capital</td><td>1.00</td><td>2.00</td><td></td></tr>
+			  <tr><td></td><td>9.00</td><td>9.00</td><td></td></tr>
+			  <tr><td>Source : Dion Global Solutions Limited</td></tr>
+			</table>`,
+			[]string{
+				"EQUITIES AND LIABILITIES|Equity Share Capital|2024-03|1|362",
+				"EQUITIES AND LIABILITIES|Equity Share Capital|2023-03|1|366",
+				"EQUITIES AND LIABILITIES|Reserves and Surplus|2024-03|2|100000.5",
+				"EQUITIES AND LIABILITIES|Reserves and Surplus|2023-03|2|nil",
+				"ASSETS|Equity Share Capital (2)|2024-03|3|1",
+				"ASSETS|Equity Share Capital (2)|2023-03|3|2",
+			},
+			false,
+		},
+		{
+			`<table class="mctable1">
+			  <tr><td>Net Sales</td><td>100</td></tr>
+			</table>`,
+			nil,
+			true,
+		},
+		{`<p>Data not available</p>`, nil, true},
+	}
+	for _, tt := range tests {
+		doc, err := goquery.NewDocumentFromReader(strings.NewReader(tt.html))
+		if err != nil {
+			t.Fatalf("invalid fixture: %v", err)
+		}
+		items, err := parseStatement(doc, "balance-sheet")
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseStatement(%s) error = %v, wantErr %v", tt.html, err, tt.wantErr)
+			continue
+		}
+		var got []string
+		for _, item := range items {
+			if item.Statement != "balance-sheet" {
+				t.Errorf("parseStatement() statement = %q, want balance-sheet", item.Statement)
+			}
+			value := "nil"
+			if item.Value != nil {
+				value = fmt.Sprint(*item.Value)
+			}
+			got = append(got, fmt.Sprintf("%s|%s|%s|%d|%s", item.Section, item.LineItem, item.Period, item.Position, value))
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseStatement(%s) = %q, want %q", tt.html, got, tt.want)
+		}
+	}
+}
+
+func TestParsePeriod(t *testing.T) {
+	tests := []struct {
+		text   string
+		want   string
+		wantOK bool
+	}{
+		{"Mar '24", "2024-03", true},
+		{"Mar 2024", "2024-03", true},
+		{" Dec\n '23 ", "2023-12", true},
+		{"Sep'22", "2022-09", true},
+		{"12 mths", "", false},
+		{"Net Sales", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := parsePeriod(tt.text)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("parsePeriod(%q) = %q, %v, want %q, %v", tt.text, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
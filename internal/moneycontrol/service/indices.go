@@ -0,0 +1,223 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/johnsonabraham/moneycontrolscraper/internal/moneycontrol/models"
+)
+
+// ErrNoIndices is returned when an index collection runs without any index configured in INDICES
+var ErrNoIndices = errors.New("no indices configured")
+
+// configuredIndices reads INDICES, a comma separated list of name|level code|constituents page
+// entries such as "NIFTY 50|in%3BNSX|top-nse-50-companies-list/9", the page being the path of the
+// index under MONEYCONTROL_INDEX_CONSTITUENTS_URL. Either the code or the page may be empty to skip
+// that part of the scrape.
+func configuredIndices(spec string) ([]models.Index, error) {
+	var indices []models.Index
+	for _, entry := range strings.Split(spec, ",") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		fields := strings.Split(entry, "|")
+		if len(fields) != 3 || strings.TrimSpace(fields[0]) == "" {
+			return nil, fmt.Errorf("index %q must be name|level code|constituents page", entry)
+		}
+		indices = append(indices, models.Index{
+			Name:             strings.TrimSpace(fields[0]),
+			LevelCode:        strings.TrimSpace(fields[1]),
+			ConstituentsPage: strings.Trim(strings.TrimSpace(fields[2]), "/"),
+		})
+	}
+	return indices, nil
+}
+
+// CaptureIndices refreshes the level and constituents of every configured index, reporting each
+// index to progress
+func (i *moneyControlService) CaptureIndices(progress *JobProgress) error {
+	indices, err := configuredIndices(i.cfg.Indices)
+	if err != nil {
+		return err
+	}
+	if len(indices) == 0 {
+		return ErrNoIndices
+	}
+	progress.SetTotal(len(indices))
+	var errs []error
+	for _, index := range indices {
+		err := i.captureIndex(index)
+		progress.Step(err)
+		if err != nil {
+			i.mlog.Error(fmt.Sprintf("Error capturing index %s", index.Name), err)
+			errs = append(errs, fmt.Errorf("%s: %w", index.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// captureIndex refreshes the level and constituents of an index, storing whichever of the two
+// succeeded when the other fails
+func (i *moneyControlService) captureIndex(index models.Index) error {
+	if err := i.moneycontrolRepository.UpsertIndex(&index); err != nil {
+		return err
+	}
+	var errs []error
+	if index.LevelCode != "" {
+		if err := i.captureIndexLevel(&index); err != nil {
+			errs = append(errs, fmt.Errorf("level: %w", err))
+		}
+	}
+	if index.ConstituentsPage != "" {
+		if err := i.captureIndexConstituents(&index); err != nil {
+			errs = append(errs, fmt.Errorf("constituents: %w", err))
+		}
+	}
+	if err := i.moneycontrolRepository.SaveIndex(&index); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// captureIndexLevel reads the current level of an index from the moneycontrol price feed
+func (i *moneyControlService) captureIndexLevel(index *models.Index) error {
+	response, err := http.Get(fmt.Sprintf(i.cfg.MoneyControlIndexLevelURL, index.LevelCode))
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("index price feed answered %s", response.Status)
+	}
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+	var payload struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return err
+	}
+	fields := make(map[string]interface{}, len(payload.Data))
+	for key, value := range payload.Data {
+		fields[strings.ToLower(key)] = value
+	}
+	level, ok := jsonNumber(fields["pricecurrent"])
+	if !ok {
+		return fmt.Errorf("no level in price feed of %s", index.LevelCode)
+	}
+	index.Level = level
+	index.Change = detailNumber(fields, []string{"pricechange", "change"})
+	index.ChangePercent = detailNumber(fields, []string{"pricepercentchange", "percentchange"})
+	now := time.Now()
+	index.LevelAt = &now
+	return nil
+}
+
+// captureIndexConstituents replaces the constituents of an index with those on its constituents page.
+// Companies missing from company_infos are left out until the next symbol collection adds them.
+func (i *moneyControlService) captureIndexConstituents(index *models.Index) error {
+	doc, err := getStockQuote(fmt.Sprintf(i.cfg.MoneyControlIndexConstituentsURL, index.ConstituentsPage))
+	if err != nil {
+		return err
+	}
+	weights := parseConstituents(doc)
+	if len(weights) == 0 {
+		// an empty page means moneycontrol failed us, not that the index has no companies
+		return fmt.Errorf("no constituents found on page %s", index.ConstituentsPage)
+	}
+	symbols := make([]string, 0, len(weights))
+	for symbol := range weights {
+		symbols = append(symbols, symbol)
+	}
+	companyIDs, err := i.moneycontrolRepository.FetchCompanyIDsBySymbols(symbols)
+	if err != nil {
+		return err
+	}
+	constituents := make([]models.IndexConstituent, 0, len(companyIDs))
+	for symbol, companyID := range companyIDs {
+		constituents = append(constituents, models.IndexConstituent{CompanyID: companyID, Weight: weights[symbol]})
+	}
+	if missing := len(weights) - len(companyIDs); missing > 0 {
+		i.mlog.Info(fmt.Sprintf("%d constituents of %s are not known companies", missing, index.Name))
+	}
+	if err := i.moneycontrolRepository.ReplaceIndexConstituents(index.ID, constituents); err != nil {
+		return err
+	}
+	now := time.Now()
+	index.ConstituentsAt = &now
+	return nil
+}
+
+// parseConstituents reads the moneycontrol symbol and weight of every company linked from the
+// tables of a constituents page. The weight comes from the column headed "Weight", 0 without one.
+func parseConstituents(doc *goquery.Document) map[string]float64 {
+	weights := make(map[string]float64)
+	doc.Find("table").Each(func(_ int, table *goquery.Selection) {
+		weightColumn := -1
+		table.Find("th").Each(func(idx int, s *goquery.Selection) {
+			if weightColumn < 0 && strings.Contains(strings.ToLower(s.Text()), "weight") {
+				weightColumn = idx
+			}
+		})
+		table.Find("tr").Each(func(_ int, row *goquery.Selection) {
+			link, found := row.Find(`a[href*="/stockpricequote/"]`).First().Attr("href")
+			if !found {
+				return
+			}
+			symbol := stockLinkSymbol(link)
+			if symbol == "" {
+				return
+			}
+			var weight float64
+			if weightColumn >= 0 {
+				cell := strings.TrimSpace(row.Find("td").Eq(weightColumn).Text())
+				weight, _ = jsonNumber(strings.TrimSuffix(cell, "%"))
+			}
+			weights[symbol] = weight
+		})
+	})
+	return weights
+}
+
+// stockLinkSymbol returns the moneycontrol symbol ending a stock page link
+func stockLinkSymbol(link string) string {
+	if idx := strings.IndexAny(link, "?#"); idx >= 0 {
+		link = link[:idx]
+	}
+	parts := strings.Split(strings.TrimRight(link, "/"), "/")
+	return parts[len(parts)-1]
+}
+
+// EnqueueIndexCollection queues a job refreshing every configured index
+func (i *moneyControlService) EnqueueIndexCollection() (*models.Job, error) {
+	indices, err := configuredIndices(i.cfg.Indices)
+	if err != nil {
+		return nil, err
+	}
+	if len(indices) == 0 {
+		return nil, ErrNoIndices
+	}
+	return i.jobs.Enqueue(models.JobCollectIndices, "", func(progress *JobProgress) error {
+		return i.CaptureIndices(progress)
+	})
+}
+
+func (i *moneyControlService) ListIndices() ([]models.Index, error) {
+	return i.moneycontrolRepository.FetchIndices()
+}
+
+// GetIndexConstituents returns the companies of an index with their weights
+func (i *moneyControlService) GetIndexConstituents(id int64) ([]models.Constituent, error) {
+	if _, err := i.moneycontrolRepository.FetchIndex(id); err != nil {
+		return nil, err
+	}
+	return i.moneycontrolRepository.FetchIndexConstituents(id)
+}
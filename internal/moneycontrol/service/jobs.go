@@ -0,0 +1,216 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/johnsonabraham/moneycontrolscraper/config"
+	"github.com/johnsonabraham/moneycontrolscraper/internal/moneycontrol/models"
+	repository "github.com/johnsonabraham/moneycontrolscraper/internal/moneycontrol/repository"
+	"github.com/kataras/golog"
+)
+
+// JobRunner runs queued scrapes in the background and keeps their state in the jobs table. Long
+// jobs (models.LongJobTypes) run at most cfg.LongJobWorkers at a time and every other job at most
+// cfg.JobWorkers at a time, so a backfill can't keep the scheduled collections waiting.
+type JobRunner struct {
+	mlog          *golog.Logger
+	jobRepository repository.JobRepository
+	slots         chan struct{}
+	longSlots     chan struct{}
+}
+
+func NewJobRunner(mlog *golog.Logger, cfg *config.AppEnvVars, jobRepository repository.JobRepository) *JobRunner {
+	runner := &JobRunner{
+		mlog:          mlog,
+		jobRepository: jobRepository,
+		slots:         make(chan struct{}, atLeastOne(cfg.JobWorkers)),
+		longSlots:     make(chan struct{}, atLeastOne(cfg.LongJobWorkers)),
+	}
+	// jobs don't survive a restart, so whatever was in flight is reported as failed
+	jobRepository.FailUnfinishedJobs("interrupted by a restart")
+	return runner
+}
+
+// Enqueue stores a queued job and runs it once a worker is free
+func (r *JobRunner) Enqueue(jobType, params string, run func(progress *JobProgress) error) (*models.Job, error) {
+	job := &models.Job{Type: jobType, Params: params, State: models.JobQueued}
+	if err := r.jobRepository.CreateJob(job); err != nil {
+		return nil, err
+	}
+	queued := *job
+	go r.run(job, run)
+	return &queued, nil
+}
+
+func (r *JobRunner) Get(id int64) (*models.Job, error) {
+	return r.jobRepository.FetchJob(id)
+}
+
+func (r *JobRunner) List(filter models.JobFilter) ([]models.Job, error) {
+	return r.jobRepository.FetchJobs(filter)
+}
+
+// RecordScheduleRun stores a firing of a scheduled scrape
+func (r *JobRunner) RecordScheduleRun(run *models.ScheduleRun) error {
+	return r.jobRepository.CreateScheduleRun(run)
+}
+
+// LatestScheduleRun returns the last firing of the schedule called name
+func (r *JobRunner) LatestScheduleRun(name string) (*models.ScheduleRun, error) {
+	return r.jobRepository.FetchLatestScheduleRun(name)
+}
+
+// LatestScheduleRunWithJob returns the last firing of the schedule called name that has a job,
+// skipped firings carrying the job they waited on
+func (r *JobRunner) LatestScheduleRunWithJob(name string) (*models.ScheduleRun, error) {
+	return r.jobRepository.FetchLatestScheduleRunWithJob(name)
+}
+
+func atLeastOne(workers int) int {
+	if workers < 1 {
+		return 1
+	}
+	return workers
+}
+
+func (r *JobRunner) run(job *models.Job, run func(progress *JobProgress) error) {
+	slots := r.slots
+	if models.LongJobTypes[job.Type] {
+		slots = r.longSlots
+	}
+	slots <- struct{}{}
+	defer func() { <-slots }()
+
+	progress := &JobProgress{job: job, jobRepository: r.jobRepository}
+	progress.update(func(job *models.Job) {
+		startedAt := time.Now()
+		job.State = models.JobRunning
+		job.StartedAt = &startedAt
+	})
+	r.mlog.Info(fmt.Sprintf("Job %d (%s) started", job.ID, job.Type))
+
+	err := func() (err error) {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				err = fmt.Errorf("job panicked: %v", recovered)
+			}
+		}()
+		return run(progress)
+	}()
+
+	progress.update(func(job *models.Job) {
+		finishedAt := time.Now()
+		job.FinishedAt = &finishedAt
+		job.State = models.JobSucceeded
+		if err != nil {
+			job.State = models.JobFailed
+			job.Error = err.Error()
+		}
+	})
+	if err != nil {
+		r.mlog.Error(fmt.Sprintf("Job %d (%s) failed", job.ID, job.Type), err)
+		return
+	}
+	r.mlog.Info(fmt.Sprintf("Job %d (%s) succeeded", job.ID, job.Type))
+}
+
+// JobProgress lets a running job report its progress. A nil *JobProgress ignores every call so
+// the same code can run outside of a job.
+type JobProgress struct {
+	mu            sync.Mutex
+	job           *models.Job
+	jobRepository repository.JobRepository
+}
+
+// SetTotal sets how many units of work the job has
+func (p *JobProgress) SetTotal(total int) {
+	p.update(func(job *models.Job) { job.Total = total })
+}
+
+// Step records one finished unit of work, failed when err is not nil
+func (p *JobProgress) Step(err error) {
+	p.update(func(job *models.Job) {
+		job.Completed++
+		if err != nil {
+			job.Failed++
+		}
+	})
+}
+
+// SetResult stores v as the JSON result of the job
+func (p *JobProgress) SetResult(v interface{}) {
+	result, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	p.update(func(job *models.Job) { job.Result = result })
+}
+
+func (p *JobProgress) update(change func(job *models.Job)) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	change(p.job)
+	p.jobRepository.SaveJob(p.job)
+}
+
+// EnqueueSymbolCollection queues a job crawling every symbol and enriching the stale companies with
+// their details, the symbol diff becoming the job result
+func (i *moneyControlService) EnqueueSymbolCollection() (*models.Job, error) {
+	return i.jobs.Enqueue(models.JobCollectSymbols, "", func(progress *JobProgress) error {
+		diff, err := i.crawlSymbols()
+		if err != nil {
+			return err
+		}
+		progress.SetResult(diff)
+		return i.CaptureAdditionalCompanyInfo(nil, false, progress)
+	})
+}
+
+// EnqueueEnrichment queues a job enriching the stale companies among symbols, or every stale company
+// when symbols is empty. force refetches the given symbols even when fresh.
+func (i *moneyControlService) EnqueueEnrichment(symbols []string, force bool) (*models.Job, error) {
+	return i.jobs.Enqueue(models.JobEnrichCompanies, strings.Join(symbols, ","), func(progress *JobProgress) error {
+		return i.CaptureAdditionalCompanyInfo(symbols, force, progress)
+	})
+}
+
+// EnqueueDividendCollection queues a job scraping the dividend history of a company
+func (i *moneyControlService) EnqueueDividendCollection(ticker string) (*models.Job, error) {
+	if _, err := i.moneycontrolRepository.ResolveCompany(ticker); err != nil {
+		return nil, err
+	}
+	return i.jobs.Enqueue(models.JobCollectDividends, ticker, func(progress *JobProgress) error {
+		progress.SetTotal(1)
+		err := i.ScrapeDividendHistory(ticker)
+		progress.Step(err)
+		return err
+	})
+}
+
+// EnqueueHistoricalDataCollection queues a job capturing the historical daily data of a company
+func (i *moneyControlService) EnqueueHistoricalDataCollection(ticker string) (*models.Job, error) {
+	if _, err := i.moneycontrolRepository.ResolveCompany(ticker); err != nil {
+		return nil, err
+	}
+	return i.jobs.Enqueue(models.JobCollectHistoricalData, ticker, func(progress *JobProgress) error {
+		progress.SetTotal(1)
+		err := i.CaptureHistoricalData(ticker)
+		progress.Step(err)
+		return err
+	})
+}
+
+func (i *moneyControlService) GetJob(id int64) (*models.Job, error) {
+	return i.jobs.Get(id)
+}
+
+func (i *moneyControlService) ListJobs(filter models.JobFilter) ([]models.Job, error) {
+	return i.jobs.List(filter)
+}
@@ -0,0 +1,34 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/johnsonabraham/moneycontrolscraper/config"
+	"github.com/johnsonabraham/moneycontrolscraper/internal/moneycontrol/models"
+	"github.com/kataras/golog"
+)
+
+func TestLongJobsDontHoldUpOtherJobs(t *testing.T) {
+	runner := NewJobRunner(golog.New(), &config.AppEnvVars{JobWorkers: 1, LongJobWorkers: 1}, &memoryJobRepository{})
+	release := make(chan struct{})
+	defer close(release)
+	blocking := func(progress *JobProgress) error {
+		<-release
+		return nil
+	}
+	// fills the long lane, the second backfill waiting behind the first one
+	runner.Enqueue(models.JobBackfillHistoricalData, "", blocking)
+	runner.Enqueue(models.JobBackfillHistoricalData, "", blocking)
+
+	ran := make(chan struct{})
+	runner.Enqueue(models.JobCollectHistoricalData, "TCS", func(progress *JobProgress) error {
+		close(ran)
+		return nil
+	})
+	select {
+	case <-ran:
+	case <-time.After(time.Second):
+		t.Fatal("collection waited on the backfills")
+	}
+}
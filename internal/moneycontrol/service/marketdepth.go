@@ -0,0 +1,135 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/johnsonabraham/moneycontrolscraper/internal/moneycontrol/models"
+)
+
+const (
+	depthLevels = 5
+
+	defaultMarketDepthLimit = 100
+	maxMarketDepthLimit     = 1000
+)
+
+// depthKey splits a best_5_set key such as "buy_price_1", "sellQty3" or "bp2" into its side,
+// field and level once lowercased and stripped of separators
+var depthKey = regexp.MustCompile(`^(buy|bid|b|sell|ask|offer|s)(price|rate|qty|quantity|p|q)([1-5])$`)
+
+// parseMarketDepth maps the best_5_set object of the company details API to bid and ask levels.
+// Keys name the side, the field and the level, values being numbers or numeric strings with
+// thousands separators. It returns nil when no level could be read.
+func parseMarketDepth(best5Set map[string]interface{}) *models.MarketDepth {
+	levels := make([]models.DepthLevel, depthLevels)
+	found := false
+	for key, value := range best5Set {
+		match := depthKey.FindStringSubmatch(strings.NewReplacer("_", "", "-", "", " ", "").Replace(strings.ToLower(key)))
+		if match == nil {
+			continue
+		}
+		number, ok := jsonNumber(value)
+		if !ok {
+			continue
+		}
+		level := &levels[match[3][0]-'1']
+		bid := match[1] == "buy" || match[1] == "bid" || match[1] == "b"
+		price := match[2] == "price" || match[2] == "rate" || match[2] == "p"
+		switch {
+		case bid && price:
+			level.BidPrice = number
+		case bid:
+			level.BidQuantity = int64(number)
+		case price:
+			level.AskPrice = number
+		default:
+			level.AskQuantity = int64(number)
+		}
+		found = true
+	}
+	if !found {
+		return nil
+	}
+	depth := &models.MarketDepth{Levels: levels}
+	for idx := range levels {
+		levels[idx].Level = idx + 1
+		depth.TotalBidQuantity += levels[idx].BidQuantity
+		depth.TotalAskQuantity += levels[idx].AskQuantity
+	}
+	return depth
+}
+
+// jsonNumber reads a decoded JSON number, or a numeric string with thousands separators
+func jsonNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case string:
+		number, err := strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(v), ",", ""), 64)
+		return number, err == nil
+	}
+	return 0, false
+}
+
+// CaptureMarketDepth reads the current order book of a company from the details API and stores it
+func (i *moneyControlService) CaptureMarketDepth(ticker string) (*models.MarketDepth, error) {
+	companyInfo, err := i.moneycontrolRepository.ResolveCompany(ticker)
+	if err != nil {
+		i.mlog.Error("Error fetching provided company", err)
+		return nil, err
+	}
+	additionalDetails, err := i.fetchCompanyDetails(*companyInfo)
+	if err != nil {
+		return nil, err
+	}
+	depth := parseMarketDepth(additionalDetails.Data.Best5Set)
+	if depth == nil {
+		return nil, fmt.Errorf("no market depth in company details of %s", companyInfo.Symbol)
+	}
+	depth.CompanyID = companyInfo.ID
+	depth.CapturedAt = time.Now()
+	if err := i.moneycontrolRepository.SaveMarketDepth(depth); err != nil {
+		i.mlog.Error(fmt.Sprintf("Failed to store market depth for %s:", companyInfo.Symbol), err)
+		return nil, err
+	}
+	return depth, nil
+}
+
+// EnqueueMarketDepthCollection queues a job capturing the order book of a company, the snapshot
+// becoming the job result
+func (i *moneyControlService) EnqueueMarketDepthCollection(ticker string) (*models.Job, error) {
+	if _, err := i.moneycontrolRepository.ResolveCompany(ticker); err != nil {
+		return nil, err
+	}
+	return i.jobs.Enqueue(models.JobCollectMarketDepth, ticker, func(progress *JobProgress) error {
+		progress.SetTotal(1)
+		depth, err := i.CaptureMarketDepth(ticker)
+		progress.Step(err)
+		if err != nil {
+			return err
+		}
+		progress.SetResult(depth)
+		return nil
+	})
+}
+
+// GetMarketDepth returns the stored order book snapshots of a company between from and to,
+// newest first and at most limit of them
+func (i *moneyControlService) GetMarketDepth(ticker string, from, to time.Time, limit int) ([]models.MarketDepth, error) {
+	companyInfo, err := i.moneycontrolRepository.ResolveCompany(ticker)
+	if err != nil {
+		i.mlog.Error("Error fetching provided company", err)
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = defaultMarketDepthLimit
+	}
+	if limit > maxMarketDepthLimit {
+		limit = maxMarketDepthLimit
+	}
+	return i.moneycontrolRepository.FetchMarketDepth(companyInfo.ID, from, to, limit)
+}
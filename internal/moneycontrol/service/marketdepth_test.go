@@ -0,0 +1,79 @@
+package service
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/johnsonabraham/moneycontrolscraper/internal/moneycontrol/models"
+)
+
+func TestParseMarketDepth(t *testing.T) {
+	tests := []struct {
+		best5Set string
+		want     *models.MarketDepth
+	}{
+		{
+			`{"buy_price_1":"3,025.40","buy_qty_1":"1,200","sell_price_1":3025.9,"sell_qty_1":450,
+			  "buy_price_2":3025.1,"buy_qty_2":300,"sell_price_2":"3026.00","sell_qty_2":"75"}`,
+			&models.MarketDepth{
+				Levels: []models.DepthLevel{
+					{Level: 1, BidPrice: 3025.4, BidQuantity: 1200, AskPrice: 3025.9, AskQuantity: 450},
+					{Level: 2, BidPrice: 3025.1, BidQuantity: 300, AskPrice: 3026, AskQuantity: 75},
+					{Level: 3}, {Level: 4}, {Level: 5},
+				},
+				TotalBidQuantity: 1500,
+				TotalAskQuantity: 525,
+			},
+		},
+		{
+			`{"bp5":101.5,"bq5":10,"sp5":102,"sq5":20,"bidRate3":100,"askQuantity3":"5","OFFER-PRICE-4":"103.25"}`,
+			&models.MarketDepth{
+				Levels: []models.DepthLevel{
+					{Level: 1}, {Level: 2},
+					{Level: 3, BidPrice: 100, AskQuantity: 5},
+					{Level: 4, AskPrice: 103.25},
+					{Level: 5, BidPrice: 101.5, BidQuantity: 10, AskPrice: 102, AskQuantity: 20},
+				},
+				TotalBidQuantity: 10,
+				TotalAskQuantity: 25,
+			},
+		},
+		// unknown keys, levels out of range and values that aren't numbers are left out
+		{`{"buy_price_6":100,"total_buy_qty":5000,"buy_price_1":"-","sell_qty_1":null}`, nil},
+		{`{}`, nil},
+	}
+	for _, tt := range tests {
+		var best5Set map[string]interface{}
+		if err := json.Unmarshal([]byte(tt.best5Set), &best5Set); err != nil {
+			t.Fatalf("invalid fixture %s: %v", tt.best5Set, err)
+		}
+		if got := parseMarketDepth(best5Set); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseMarketDepth(%s) = %+v, want %+v", tt.best5Set, got, tt.want)
+		}
+	}
+}
+
+func TestJSONNumber(t *testing.T) {
+	tests := []struct {
+		value  interface{}
+		want   float64
+		wantOK bool
+	}{
+		{float64(3025.4), 3025.4, true},
+		{"3025.40", 3025.4, true},
+		{" 1,23,456.5 ", 123456.5, true},
+		{"0", 0, true},
+		{"", 0, false},
+		{"-", 0, false},
+		{"N/A", 0, false},
+		{nil, 0, false},
+		{true, 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := jsonNumber(tt.value)
+		if ok != tt.wantOK || (ok && got != tt.want) {
+			t.Errorf("jsonNumber(%#v) = %v, %v, want %v, %v", tt.value, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
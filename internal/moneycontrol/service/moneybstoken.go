@@ -0,0 +1,121 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/johnsonabraham/moneycontrolscraper/config"
+	"github.com/kataras/golog"
+)
+
+var (
+	// tokenRefreshAhead is how long before expiry a cached token is replaced
+	tokenRefreshAhead = time.Minute
+	// tokenDefaultTTL is how long a token without an exp claim is reused
+	tokenDefaultTTL = 10 * time.Minute
+
+	// ErrEmptyMoneyBSToken is returned when the MoneyBS auth endpoint answers 2xx without a token
+	ErrEmptyMoneyBSToken = errors.New("MoneyBS auth returned an empty token")
+)
+
+// MoneyBSAuthError is returned when the MoneyBS auth endpoint answers with a non 2xx status
+type MoneyBSAuthError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *MoneyBSAuthError) Error() string {
+	return fmt.Sprintf("MoneyBS auth failed with status %d: %s", e.StatusCode, e.Body)
+}
+
+// moneyBSTokenManager caches the MoneyBS bearer token and refreshes it ahead of its expiry
+type moneyBSTokenManager struct {
+	mlog   *golog.Logger
+	cfg    *config.AppEnvVars
+	client *http.Client
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+func newMoneyBSTokenManager(mlog *golog.Logger, cfg *config.AppEnvVars, client *http.Client) *moneyBSTokenManager {
+	return &moneyBSTokenManager{mlog: mlog, cfg: cfg, client: client}
+}
+
+// Token returns the cached token, fetching a new one when missing or about to expire
+func (t *moneyBSTokenManager) Token() (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.token != "" && time.Now().Add(tokenRefreshAhead).Before(t.expiresAt) {
+		return t.token, nil
+	}
+	token, err := t.fetch()
+	if err != nil {
+		return "", err
+	}
+	t.token = token
+	t.expiresAt = tokenExpiry(token)
+	return t.token, nil
+}
+
+// Invalidate drops the cached token so the next call to Token fetches a new one
+func (t *moneyBSTokenManager) Invalidate() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.token = ""
+}
+
+func (t *moneyBSTokenManager) fetch() (string, error) {
+	req, err := http.NewRequest("GET", t.cfg.MoneyBSBaseURL+t.cfg.MoneyBSAuthEndpoint, nil)
+	if err != nil {
+		t.mlog.Error(err)
+		return "", err
+	}
+	req.Header.Add("x-api-key", t.cfg.MoneyBSAPIKey)
+	response, err := t.client.Do(req)
+	if err != nil {
+		t.mlog.Error(err)
+		return "", err
+	}
+
+	defer response.Body.Close()
+	// Read response body
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		t.mlog.Error(err)
+		return "", err
+	}
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return "", &MoneyBSAuthError{StatusCode: response.StatusCode, Body: string(body)}
+	}
+	token := strings.Trim(strings.TrimSpace(string(body)), "\"")
+	if token == "" {
+		return "", ErrEmptyMoneyBSToken
+	}
+	return token, nil
+}
+
+// tokenExpiry reads the exp claim of a JWT, falling back to tokenDefaultTTL for opaque tokens
+func tokenExpiry(token string) time.Time {
+	parts := strings.Split(token, ".")
+	if len(parts) == 3 {
+		payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+		if err == nil {
+			var claims struct {
+				Exp int64 `json:"exp"`
+			}
+			if json.Unmarshal(payload, &claims) == nil && claims.Exp > 0 {
+				return time.Unix(claims.Exp, 0)
+			}
+		}
+	}
+	return time.Now().Add(tokenDefaultTTL)
+}
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTokenExpiry(t *testing.T) {
+	const header = "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9"
+	exp := time.Unix(1792368000, 0)
+	tests := []struct {
+		token string
+		want  time.Time // zero when tokenDefaultTTL applies
+	}{
+		// {"sub":"scraper","exp":1792368000}
+		{header + ".eyJzdWIiOiJzY3JhcGVyIiwiZXhwIjoxNzkyMzY4MDAwfQ.c2lnbmF0dXJl", exp},
+		{header + ".eyJzdWIiOiJzY3JhcGVyIiwiZXhwIjoxNzkyMzY4MDAwfQ==.c2lnbmF0dXJl", exp},
+		// {"sub":"scraper"}
+		{header + ".eyJzdWIiOiJzY3JhcGVyIn0.c2lnbmF0dXJl", time.Time{}},
+		// {"sub":"scraper","exp":"soon"}
+		{header + ".eyJzdWIiOiJzY3JhcGVyIiwiZXhwIjoic29vbiJ9.c2lnbmF0dXJl", time.Time{}},
+		{header + ".not base64.c2lnbmF0dXJl", time.Time{}},
+		{"4f1c2e0a9b7d4e3f8a6b", time.Time{}},
+		{"", time.Time{}},
+	}
+	for _, tt := range tests {
+		before := time.Now()
+		got := tokenExpiry(tt.token)
+		if !tt.want.IsZero() {
+			if !got.Equal(tt.want) {
+				t.Errorf("tokenExpiry(%q) = %s, want %s", tt.token, got, tt.want)
+			}
+			continue
+		}
+		if got.Before(before.Add(tokenDefaultTTL)) || got.After(time.Now().Add(tokenDefaultTTL)) {
+			t.Errorf("tokenExpiry(%q) = %s, want %s from now", tt.token, got, tokenDefaultTTL)
+		}
+	}
+}
@@ -1,8 +1,8 @@
 package service
 
 import (
-	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -20,12 +20,37 @@ import (
 )
 
 var (
-	stocksURL = make(models.StocksInfo)
-	baseURL   = "https://www.moneycontrol.com/technical-analysis"
+	baseURL = "https://www.moneycontrol.com/technical-analysis"
+
+	// ErrCompanyNotFound is returned when a ticker cannot be resolved to a moneycontrol page
+	ErrCompanyNotFound = errors.New("company not found")
+
+	// ErrAmbiguousCompany is returned when an identifier matches more than one company
+	ErrAmbiguousCompany = repository.ErrAmbiguousCompany
+
+	// ErrInvalidTimeframe is returned when a technical analysis timeframe is not daily, weekly or monthly
+	ErrInvalidTimeframe = errors.New("invalid timeframe")
+
+	// ErrBSEHistoryNotConfigured is returned when historical data is collected for a company listed
+	// only on BSE while MONEYCONTROL_BSE_HISTORICAL_DATA_URL is empty
+	ErrBSEHistoryNotConfigured = errors.New("MONEYCONTROL_BSE_HISTORICAL_DATA_URL is not configured")
 )
 
+// historicalDataJson is the moneycontrol chart history response with one entry per candle in each array
+type historicalDataJson struct {
+	Status    string    `json:"s"`
+	Timestamp []int64   `json:"t"`
+	Open      []float64 `json:"o"`
+	High      []float64 `json:"h"`
+	Low       []float64 `json:"l"`
+	Close     []float64 `json:"c"`
+	Volume    []int64   `json:"v"`
+}
+
 type CompanyAdditionalDetailsJson struct {
 	Data data `json:"data"`
+	// raw is the response body the fields were decoded from
+	raw json.RawMessage
 }
 type data struct {
 	BSEID        string                 `json:"BSEID"`
@@ -36,25 +61,61 @@ type data struct {
 	Best5Set     map[string]interface{} `json:"best_5_set"`
 }
 
-// GetCompanyList returns the list of all the companies tracked via stockrate
-func GetCompanyList() (list []string) {
-	for key := range stocksURL {
-		list = append(list, key)
-	}
-	return
-}
-
 type MoneycontrolService interface {
-	CaptureSymbols() error
+	EnqueueSymbolCollection() (*models.Job, error)
+	EnqueueEnrichment(symbols []string, force bool) (*models.Job, error)
+	EnqueueDividendCollection(ticker string) (*models.Job, error)
+	EnqueueHistoricalDataCollection(ticker string) (*models.Job, error)
+	GetJob(id int64) (*models.Job, error)
+	ListJobs(filter models.JobFilter) ([]models.Job, error)
+	GetSchedules() ([]models.ScheduleStatus, error)
+	SearchCompanies(filter models.CompanyFilter) (models.CompanyPage, error)
+	GetCompany(ticker string) (*models.CompanyInfo, error)
+	GetPeers(ticker string, limit int) (models.PeerGroup, error)
+	GetSectorSummaries() ([]models.SectorSummary, error)
+	CaptureIndices(progress *JobProgress) error
+	EnqueueIndexCollection() (*models.Job, error)
+	ListIndices() ([]models.Index, error)
+	GetIndexConstituents(id int64) ([]models.Constituent, error)
 	ScrapeDividendHistory(companyName string) error
+	GetDividendHistory(ticker string) ([]models.Dividend, error)
+	EnqueueDividendsBulk(selection models.BulkSelection) (*models.Job, error)
 	CaptureHistoricalData(ticker string) error
+	GetHistoricalData(ticker string, from, to time.Time) ([]models.Candle, error)
+	ScrapeCorporateActions(ticker string) error
+	EnqueueCorporateActionsCollection(ticker string) (*models.Job, error)
+	GetCorporateActions(ticker, actionType string) ([]models.CorporateAction, error)
+	ScrapeAnnouncements(ticker string) error
+	EnqueueAnnouncementCollection(ticker string) (*models.Job, error)
+	GetAnnouncements(ticker string, filter models.AnnouncementFilter) (models.AnnouncementPage, error)
+	ScrapeShareholding(ticker string) error
+	EnqueueShareholdingCollection(ticker string) (*models.Job, error)
+	GetShareholding(ticker string) ([]models.ShareholdingQuarter, error)
+	ScrapeFinancials(ticker string) error
+	EnqueueFinancialsCollection(ticker string) (*models.Job, error)
+	GetFinancials(ticker, statement, period string) ([]models.FinancialLineItem, error)
+	CaptureMarketDepth(ticker string) (*models.MarketDepth, error)
+	EnqueueMarketDepthCollection(ticker string) (*models.Job, error)
+	GetMarketDepth(ticker string, from, to time.Time, limit int) ([]models.MarketDepth, error)
+	EnqueueBackfill() (*models.Job, error)
+	ResumeBackfill()
+	GetBackfillStatus() (*models.BackfillRun, error)
+	GetPrice(company string, timeframe models.Timeframe) (models.StockPrice, error)
+	GetTechnicals(company string, timeframe models.Timeframe) (models.StockTechnicals, error)
+	GetMovingAverage(company string, timeframe models.Timeframe) (models.StockMovingAverage, error)
+	GetPivotLevels(company string, timeframe models.Timeframe) (models.StockPivotLevels, error)
+	GetTechnicalSnapshot(company string, timeframe models.Timeframe) (models.TechnicalSnapshot, error)
 }
 
-func NewMoneyControlService(mlog *golog.Logger, cfg *config.AppEnvVars, moneycontrolRepository repository.MoneycontrolRepository) *moneyControlService {
+func NewMoneyControlService(mlog *golog.Logger, cfg *config.AppEnvVars, moneycontrolRepository repository.MoneycontrolRepository, sink Sink, jobs *JobRunner, calendar *MarketCalendar) *moneyControlService {
 	return &moneyControlService{
 		mlog:                   mlog,
 		cfg:                    cfg,
 		moneycontrolRepository: moneycontrolRepository,
+		sink:                   sink,
+		jobs:                   jobs,
+		calendar:               calendar,
+		symbols:                newSymbolCache(),
 	}
 }
 
@@ -62,50 +123,116 @@ type moneyControlService struct {
 	mlog                   *golog.Logger
 	cfg                    *config.AppEnvVars
 	moneycontrolRepository repository.MoneycontrolRepository
+	symbols                *symbolCache
+	sink                   Sink
+	jobs                   *JobRunner
+	calendar               *MarketCalendar
+	schedules              []*scheduleEntry
+	backfill               backfillState
 }
 
 // GetPrice returns current price, previous close, open, variation, percentage and volume for a company
-func GetPrice(company string) (models.StockPrice, error) {
-	var stockPrice models.StockPrice
-	url, err := getURL(company)
+func (i *moneyControlService) GetPrice(company string, timeframe models.Timeframe) (models.StockPrice, error) {
+	doc, err := i.getTechnicalDocument(company, timeframe)
 	if err != nil {
-		return stockPrice, err
+		return models.StockPrice{}, err
 	}
-	doc, err := getStockQuote(url)
+	return parsePrice(doc), nil
+}
+
+// GetTechnicals returns the technical valuations of a company with indications
+func (i *moneyControlService) GetTechnicals(company string, timeframe models.Timeframe) (models.StockTechnicals, error) {
+	doc, err := i.getTechnicalDocument(company, timeframe)
 	if err != nil {
-		return stockPrice, fmt.Errorf("error in reading stock Price")
+		return nil, err
 	}
-	doc.Find(".bsedata_bx").Each(func(i int, s *goquery.Selection) {
-		stockPrice.BSE.Price, _ = strconv.ParseFloat(s.Find(".span_price_wrap").Text(), 64)
-		stockPrice.BSE.PreviousClose, _ = strconv.ParseFloat(s.Find(".priceprevclose").Text(), 64)
-		stockPrice.BSE.Open, _ = strconv.ParseFloat(s.Find(".priceopen").Text(), 64)
-		stockPrice.BSE.Variation, _ = strconv.ParseFloat(strings.Split(s.Find(".span_price_change_prcnt").Text(), " ")[0], 64)
-		stockPrice.BSE.Percentage, _ = strconv.ParseFloat(strings.Split(strings.Split(s.Find(".span_price_change_prcnt").Text(), "%")[0], "(")[1], 64)
-		stockPrice.BSE.Volume, _ = strconv.ParseInt(strings.ReplaceAll(s.Find(".volume_data").Text(), ",", ""), 10, 64)
-	})
-	doc.Find(".nsedata_bx").Each(func(i int, s *goquery.Selection) {
-		stockPrice.NSE.Price, _ = strconv.ParseFloat(s.Find(".span_price_wrap").Text(), 64)
-		stockPrice.NSE.PreviousClose, _ = strconv.ParseFloat(s.Find(".priceprevclose").Text(), 64)
-		stockPrice.NSE.Open, _ = strconv.ParseFloat(s.Find(".priceopen").Text(), 64)
-		stockPrice.NSE.Variation, _ = strconv.ParseFloat(strings.Split(s.Find(".span_price_change_prcnt").Text(), " ")[0], 64)
-		stockPrice.NSE.Percentage, _ = strconv.ParseFloat(strings.Split(strings.Split(s.Find(".span_price_change_prcnt").Text(), "%")[0], "(")[1], 64)
-		stockPrice.NSE.Volume, _ = strconv.ParseInt(strings.ReplaceAll(s.Find(".volume_data").Text(), ",", ""), 10, 64)
-	})
-	return stockPrice, nil
+	return parseTechnicals(doc, timeframe), nil
 }
 
-// GetTechnicals returns the technical valuations of a company with indications
-func GetTechnicals(company string) (models.StockTechnicals, error) {
-	stockTechnicals := make(models.StockTechnicals)
-	url, err := getURL(company)
+// GetMovingAverage returns the 5, 10, 20, 50, 100, 200 days moving average respectively
+func (i *moneyControlService) GetMovingAverage(company string, timeframe models.Timeframe) (models.StockMovingAverage, error) {
+	doc, err := i.getTechnicalDocument(company, timeframe)
+	if err != nil {
+		return nil, err
+	}
+	return parseMovingAverage(doc, timeframe), nil
+}
+
+// GetPivotLevels returns the important pivot levels of a stock given in order R1, R2, R3, Pivot, S1, S2, S3
+func (i *moneyControlService) GetPivotLevels(company string, timeframe models.Timeframe) (models.StockPivotLevels, error) {
+	doc, err := i.getTechnicalDocument(company, timeframe)
+	if err != nil {
+		return nil, err
+	}
+	return parsePivotLevels(doc, timeframe), nil
+}
+
+// GetTechnicalSnapshot returns price, technicals, moving averages and pivot levels of a company
+// parsed from a single fetch of its technical analysis page
+func (i *moneyControlService) GetTechnicalSnapshot(company string, timeframe models.Timeframe) (models.TechnicalSnapshot, error) {
+	doc, err := i.getTechnicalDocument(company, timeframe)
+	if err != nil {
+		return models.TechnicalSnapshot{}, err
+	}
+	return models.TechnicalSnapshot{
+		Price:          parsePrice(doc),
+		Technicals:     parseTechnicals(doc, timeframe),
+		MovingAverages: parseMovingAverage(doc, timeframe),
+		PivotLevels:    parsePivotLevels(doc, timeframe),
+		FetchedAt:      time.Now(),
+	}, nil
+}
+
+// getTechnicalDocument resolves the company and fetches its technical analysis page for the timeframe
+func (i *moneyControlService) getTechnicalDocument(company string, timeframe models.Timeframe) (*goquery.Document, error) {
+	url, err := i.getURL(company, timeframe)
 	if err != nil {
 		return nil, err
 	}
 	doc, err := getStockQuote(url)
 	if err != nil {
-		return nil, fmt.Errorf("error in reading stock Technicals %v", err.Error())
+		return nil, fmt.Errorf("error in reading technical analysis page %v", err.Error())
+	}
+	return doc, nil
+}
+
+// sectionID returns the element id of a technical analysis table, which moneycontrol suffixes
+// with the first letter of the timeframe (techindd, techindw, techindm)
+func sectionID(prefix string, timeframe models.Timeframe) string {
+	if timeframe == "" {
+		timeframe = models.TimeframeDaily
 	}
-	doc.Find("#techindd").Find("tbody").Find("tr").Each(func(i int, s *goquery.Selection) {
+	return prefix + string(timeframe[0])
+}
+
+func parsePrice(doc *goquery.Document) models.StockPrice {
+	var stockPrice models.StockPrice
+	doc.Find(".bsedata_bx").Each(func(i int, s *goquery.Selection) {
+		stockPrice.BSE = parseSymbolPrice(s)
+	})
+	doc.Find(".nsedata_bx").Each(func(i int, s *goquery.Selection) {
+		stockPrice.NSE = parseSymbolPrice(s)
+	})
+	return stockPrice
+}
+
+func parseSymbolPrice(s *goquery.Selection) models.SymbolPriceValue {
+	var price models.SymbolPriceValue
+	change := s.Find(".span_price_change_prcnt").Text()
+	price.Price, _ = strconv.ParseFloat(s.Find(".span_price_wrap").Text(), 64)
+	price.PreviousClose, _ = strconv.ParseFloat(s.Find(".priceprevclose").Text(), 64)
+	price.Open, _ = strconv.ParseFloat(s.Find(".priceopen").Text(), 64)
+	price.Variation, _ = strconv.ParseFloat(strings.Split(change, " ")[0], 64)
+	if percentage := strings.Split(strings.Split(change, "%")[0], "("); len(percentage) > 1 {
+		price.Percentage, _ = strconv.ParseFloat(percentage[1], 64)
+	}
+	price.Volume, _ = strconv.ParseInt(strings.ReplaceAll(s.Find(".volume_data").Text(), ",", ""), 10, 64)
+	return price
+}
+
+func parseTechnicals(doc *goquery.Document, timeframe models.Timeframe) models.StockTechnicals {
+	stockTechnicals := make(models.StockTechnicals)
+	doc.Find(sectionID("#techind", timeframe)).Find("tbody").Find("tr").Each(func(i int, s *goquery.Selection) {
 		symbol := strings.Split(strings.Split(s.Find("td").First().Text(), "(")[0], "%")[0]
 		level, _ := strconv.ParseFloat(strings.ReplaceAll(s.Find("td").Find("strong").First().Text(), ",", ""), 64)
 		indication := s.Find("td").Find("strong").Last().Text()
@@ -113,21 +240,12 @@ func GetTechnicals(company string) (models.StockTechnicals, error) {
 			stockTechnicals[symbol] = models.TechnicalValue{Level: level, Indication: indication}
 		}
 	})
-	return stockTechnicals, nil
+	return stockTechnicals
 }
 
-// GetMovingAverage returns the 5, 10, 20, 50, 100, 200 days moving average respectively
-func GetMovingAverage(company string) (models.StockMovingAverage, error) {
+func parseMovingAverage(doc *goquery.Document, timeframe models.Timeframe) models.StockMovingAverage {
 	stockMovingAverage := make(models.StockMovingAverage)
-	url, err := getURL(company)
-	if err != nil {
-		return nil, err
-	}
-	doc, err := getStockQuote(url)
-	if err != nil {
-		return nil, fmt.Errorf("error in reading stock Moving Averages %v", err.Error())
-	}
-	doc.Find("#movingavgd").Find("tbody").Find("tr").Each(func(i int, s *goquery.Selection) {
+	doc.Find(sectionID("#movingavg", timeframe)).Find("tbody").Find("tr").Each(func(i int, s *goquery.Selection) {
 		period, _ := strconv.Atoi(s.Find("td").First().Text())
 		sma, _ := strconv.ParseFloat(strings.ReplaceAll(s.Find("td").Find("strong").First().Text(), ",", ""), 64)
 		indication := s.Find("td").Find("strong").Last().Text()
@@ -135,21 +253,12 @@ func GetMovingAverage(company string) (models.StockMovingAverage, error) {
 			stockMovingAverage[period] = models.MovingAverageValue{SMA: sma, Indication: indication}
 		}
 	})
-	return stockMovingAverage, nil
+	return stockMovingAverage
 }
 
-// GetPivotLevels returns the important pivot levels of a stock given in order R1, R2, R3, Pivot, S1, S2, S3
-func GetPivotLevels(company string) (models.StockPivotLevels, error) {
+func parsePivotLevels(doc *goquery.Document, timeframe models.Timeframe) models.StockPivotLevels {
 	stockPivotLevels := make(models.StockPivotLevels)
-	url, err := getURL(company)
-	if err != nil {
-		return nil, err
-	}
-	doc, err := getStockQuote(url)
-	if err != nil {
-		return nil, fmt.Errorf("error in reading stock Pivot Levels %v", err.Error())
-	}
-	doc.Find("#pevotld").Find("table").First().Find("tbody").Find("tr").Each(func(i int, s *goquery.Selection) {
+	doc.Find(sectionID("#pevotl", timeframe)).Find("table").First().Find("tbody").Find("tr").Each(func(i int, s *goquery.Selection) {
 		pivotType := s.Find("td").First().Text()
 		if pivotType != "" {
 			var levels []float64
@@ -157,32 +266,48 @@ func GetPivotLevels(company string) (models.StockPivotLevels, error) {
 				level, _ := strconv.ParseFloat(strings.ReplaceAll(s.Text(), ",", ""), 64)
 				levels = append(levels, level)
 			})
+			if len(levels) < 7 {
+				return
+			}
 			stockPivotLevels[pivotType] = models.PivotPointsValue{
 				R1: levels[0], R2: levels[1], R3: levels[2], Pivot: levels[3], S1: levels[4], S2: levels[5], S3: levels[6],
 			}
 		}
 	})
-	return stockPivotLevels, nil
+	return stockPivotLevels
 }
 
-// getURL checks whether we can read data for company and returns its data source URL
-func getURL(company string) (URL string, err error) {
-	if val, found := stocksURL[strings.ToLower(company)]; found {
-		URL = baseURL + "/" + val.Company + "/" + val.Symbol + "/daily"
-		return
+// getURL checks whether we can read data for company and returns its data source URL for the timeframe,
+// defaulting to daily
+func (i *moneyControlService) getURL(company string, timeframe models.Timeframe) (URL string, err error) {
+	switch timeframe {
+	case "":
+		timeframe = models.TimeframeDaily
+	case models.TimeframeDaily, models.TimeframeWeekly, models.TimeframeMonthly:
+	default:
+		return "", ErrInvalidTimeframe
+	}
+	val, err := i.resolveStock(company)
+	if err != nil {
+		return "", err
 	}
-	return "", fmt.Errorf("company not found")
+	URL = baseURL + "/" + val.Company + "/" + val.Symbol + "/" + string(timeframe)
+	return
 }
 
-// Here stocks information necessary is saved and stored, which is calculated everytime package is imported
-func (i *moneyControlService) CaptureSymbols() error {
+// crawlSymbols reads the moneycontrol alphabetical stock listing, stores every company found and
+// reports how the listing changed
+func (i *moneyControlService) crawlSymbols() (models.SymbolDiff, error) {
 	var companyInfos []models.CompanyInfo
 	capAlphabets := []string{"A", "B", "C", "D", "E", "E", "F", "G", "H", "I", "J", "K", "L", "M", "N", "O", "P", "Q", "R", "S", "T", "U", "V", "W", "X", "Y", "Z"}
 	for _, char := range capAlphabets {
 		doc, err := getStockQuote(i.cfg.MoneyControlSymbolURL + char)
 		if err != nil {
+			// a partial crawl would delist every company of the missing page
 			i.mlog.Error("Error in fetching stock URLs ", err.Error())
+			return models.SymbolDiff{}, err
 		}
+		found := len(companyInfos)
 		doc.Find(".bl_12").Each(func(i int, s *goquery.Selection) {
 			var companyInfo models.CompanyInfo
 			link, _ := s.Attr("href")
@@ -196,57 +321,121 @@ func (i *moneyControlService) CaptureSymbols() error {
 				companyInfos = append(companyInfos, companyInfo)
 			}
 		})
+		if len(companyInfos) == found {
+			// an error page without listings would delist every company of the letter just the same
+			err := fmt.Errorf("no companies listed on symbol page %s", char)
+			i.mlog.Error("Error in fetching stock URLs ", err.Error())
+			return models.SymbolDiff{}, err
+		}
 	}
-	if err := i.moneycontrolRepository.InsertMoneyControlSymbols(companyInfos); err != nil {
+	diff, err := i.moneycontrolRepository.RefreshMoneyControlSymbols(companyInfos)
+	if err != nil {
 		i.mlog.Error("Error while saving Symbols")
-		return err
+		return diff, err
 	}
-	i.mlog.Info(fmt.Sprintf("Captured %s Symbols", strconv.Itoa(len(companyInfos))))
-	go i.CaptureAdditionalCompanyInfo(companyInfos)
-	return nil
+	i.mlog.Info(fmt.Sprintf("Captured %s Symbols, %d added, %d removed, %d renamed", strconv.Itoa(len(companyInfos)),
+		len(diff.Added), len(diff.Removed), len(diff.Renamed)))
+	i.refreshSymbolCache()
+	return diff, nil
 }
 
-func (i *moneyControlService) CaptureAdditionalCompanyInfo(companyInfos []models.CompanyInfo) {
-	for _, companyInfo := range companyInfos {
-		time.Sleep(5 * time.Second)
-		response, err := http.Get(fmt.Sprintf(i.cfg.MoneyControlCompDetailsUrl, companyInfo.Symbol))
-		if err != nil {
-			i.mlog.Error(fmt.Sprintf("Error while saving gathering additional data for %s", companyInfo.Symbol), err)
-		}
-		defer response.Body.Close()
-
-		body, err := ioutil.ReadAll(response.Body)
-		if err != nil {
-			i.mlog.Error(fmt.Sprintf("Failed to read the response body while fetching addition data for %s:",
-				companyInfo.Symbol), err)
-			continue
+// CaptureAdditionalCompanyInfo enriches companies with their details API, reporting each company to
+// progress. Only companies missing details or enriched more than cfg.EnrichmentMaxAge ago are fetched,
+// so an interrupted run picks up where it stopped; force refetches the given symbols regardless.
+// Empty symbols covers every company.
+func (i *moneyControlService) CaptureAdditionalCompanyInfo(symbols []string, force bool, progress *JobProgress) error {
+	var staleBefore time.Time
+	if !force || len(symbols) == 0 {
+		staleBefore = time.Now().Add(-i.cfg.EnrichmentMaxAge)
+	}
+	companyInfos, err := i.moneycontrolRepository.FetchCompaniesToEnrich(symbols, staleBefore)
+	if err != nil {
+		i.mlog.Error("Error fetching companies to enrich", err)
+		return err
+	}
+	i.mlog.Info(fmt.Sprintf("Collecting additional info for %d companies", len(companyInfos)))
+	progress.SetTotal(len(companyInfos))
+	for idx, companyInfo := range companyInfos {
+		if idx > 0 {
+			time.Sleep(i.cfg.EnrichmentRequestInterval)
 		}
-		var additionalDetails CompanyAdditionalDetailsJson
-		err = json.Unmarshal(body, &additionalDetails)
+		err := i.enrichCompany(companyInfo)
+		progress.Step(err)
 		if err != nil {
-			i.mlog.Error(fmt.Sprintf("Failed to unmarshall the response body while fetching addition data for %s:",
-				companyInfo.Symbol), err)
-			continue
-		}
-		companyInfo.BSEID = additionalDetails.Data.BSEID
-		companyInfo.MarketCap = additionalDetails.Data.MKTCAP
-		companyInfo.NSEID = additionalDetails.Data.NSEID
-		companyInfo.MainSectorDetails = additionalDetails.Data.MainSector
-		companyInfo.SubSectorDetails = additionalDetails.Data.NewSubSector
-		if err := i.moneycontrolRepository.UpdateSymbol(companyInfo); err != nil {
-			i.mlog.Error(fmt.Sprintf("Failed to update additional company info for %s:",
-				companyInfo.Symbol), err)
 			continue
 		}
 		i.mlog.Info(fmt.Sprintf("Done collecting additional info for %s", companyInfo.Company))
 	}
 	i.mlog.Info("Done collecting additional info for companies")
+	i.refreshSymbolCache()
+	return nil
+}
+
+// enrichCompany fetches the details API of a company and stores the payload with its exchange ids,
+// market cap, sectors and valuation fields
+func (i *moneyControlService) enrichCompany(companyInfo models.CompanyInfo) error {
+	additionalDetails, err := i.fetchCompanyDetails(companyInfo)
+	if err != nil {
+		return err
+	}
+	companyInfo.BSEID = additionalDetails.Data.BSEID
+	companyInfo.MarketCap = additionalDetails.Data.MKTCAP
+	companyInfo.NSEID = additionalDetails.Data.NSEID
+	companyInfo.MainSectorDetails = additionalDetails.Data.MainSector
+	companyInfo.SubSectorDetails = additionalDetails.Data.NewSubSector
+	companyInfo.MoreData = additionalDetails.raw
+	applyDetailFields(&companyInfo, additionalDetails.raw)
+	enrichedAt := time.Now()
+	companyInfo.EnrichedAt = &enrichedAt
+	if err := i.moneycontrolRepository.UpdateSymbol(companyInfo); err != nil {
+		i.mlog.Error(fmt.Sprintf("Failed to update additional company info for %s:",
+			companyInfo.Symbol), err)
+		return err
+	}
+	if err := i.sink.PublishCompanyInfo(companyInfo); err != nil {
+		i.mlog.Error(fmt.Sprintf("Failed to publish additional company info for %s:",
+			companyInfo.Symbol), err)
+	}
+	if depth := parseMarketDepth(additionalDetails.Data.Best5Set); depth != nil {
+		depth.CompanyID = companyInfo.ID
+		depth.CapturedAt = enrichedAt
+		if err := i.moneycontrolRepository.SaveMarketDepth(depth); err != nil {
+			i.mlog.Error(fmt.Sprintf("Failed to store market depth for %s:", companyInfo.Symbol), err)
+		}
+	}
+	return nil
+}
+
+// fetchCompanyDetails reads the moneycontrol company details API of a company
+func (i *moneyControlService) fetchCompanyDetails(companyInfo models.CompanyInfo) (*CompanyAdditionalDetailsJson, error) {
+	response, err := http.Get(fmt.Sprintf(i.cfg.MoneyControlCompDetailsUrl, companyInfo.Symbol))
+	if err != nil {
+		i.mlog.Error(fmt.Sprintf("Error while saving gathering additional data for %s", companyInfo.Symbol), err)
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		i.mlog.Error(fmt.Sprintf("Failed to read the response body while fetching addition data for %s:",
+			companyInfo.Symbol), err)
+		return nil, err
+	}
+	var additionalDetails CompanyAdditionalDetailsJson
+	err = json.Unmarshal(body, &additionalDetails)
+	if err != nil {
+		i.mlog.Error(fmt.Sprintf("Failed to unmarshall the response body while fetching addition data for %s:",
+			companyInfo.Symbol), err)
+		return nil, err
+	}
+	additionalDetails.raw = body
+	return &additionalDetails, nil
 }
 
 // Captures and stores dividend data of the provided company
 func (i *moneyControlService) ScrapeDividendHistory(ticker string) error {
 	var dividendHistory []models.Dividend
-	companyInfo, err := i.moneycontrolRepository.FetchCompanyByNameConstant(ticker)
+	companyInfo, err := i.moneycontrolRepository.ResolveCompany(ticker)
 	if err != nil {
 		i.mlog.Error("Error fetching provided company", err)
 		return err
@@ -254,6 +443,7 @@ func (i *moneyControlService) ScrapeDividendHistory(ticker string) error {
 	response, err := http.Get(fmt.Sprintf(i.cfg.MoneyControlDividendURL, companyInfo.CompanyName, companyInfo.Symbol))
 	if err != nil {
 		i.mlog.Error(fmt.Sprintf("Error while scraping dividend data for %s", ticker), err)
+		return err
 	}
 	defer response.Body.Close()
 
@@ -261,19 +451,22 @@ func (i *moneyControlService) ScrapeDividendHistory(ticker string) error {
 	doc, err := goquery.NewDocumentFromReader(response.Body)
 	if err != nil {
 		i.mlog.Error(fmt.Sprintf("Error parsing response of dividend page for %s", ticker), err)
+		return err
 	}
 	// Scrape historical dividend data
 	doc.Find("table.mctable1>tbody>tr").Each(func(count int, s *goquery.Selection) {
 		var dividend models.Dividend
 		var dateFormat = "02-01-2006"
-		t, err := time.Parse(dateFormat, s.Find("td:nth-child(1)").Text())
+		t, err := time.ParseInLocation(dateFormat, s.Find("td:nth-child(1)").Text(), MarketLocation)
 		if err != nil {
 			i.mlog.Error(fmt.Sprintf("Error converting dividend announcement date for %s", ticker), err)
 		}
 		dividend.AnnouncementDate = t.Unix()
-		t, err = time.Parse(dateFormat, s.Find("td:nth-child(2)").Text())
+		t, err = time.ParseInLocation(dateFormat, s.Find("td:nth-child(2)").Text(), MarketLocation)
 		if err != nil {
+			// the ex date keys the stored row, so skip entries without one
 			i.mlog.Error(fmt.Sprintf("Error converting dividend ex date for %s", ticker), err)
+			return
 		}
 		dividend.ExDate = t.Unix()
 		dividend.DividendType = s.Find("td:nth-child(3)").Text()
@@ -289,52 +482,49 @@ func (i *moneyControlService) ScrapeDividendHistory(ticker string) error {
 		dividendHistory = append(dividendHistory, dividend)
 
 	})
-	token, err := i.GetMoneyBSToken(i.cfg)
-	if err != nil {
-		i.mlog.Error("Error while generating token for MoneyBS", err)
+	if err := i.moneycontrolRepository.UpsertDividends(companyInfo.ID, dividendHistory); err != nil {
+		i.mlog.Error(fmt.Sprintf("Error saving dividend history for %s", ticker), err)
 		return err
 	}
-	jsonBody, err := json.Marshal(dividendHistory)
-	if err != nil {
-		i.mlog.Error("Error while marshalling dividend data", err)
-		return nil
-	}
-
-	req, err := http.NewRequest("POST", fmt.Sprintf(i.cfg.MoneyBSBaseURL+i.cfg.MoneyBSHistoricalDividendDataEndpoint, companyInfo.NSEID), bytes.NewBuffer(jsonBody))
-	if err != nil {
-		i.mlog.Error(err)
-		return err
+	// the history is stored, a sink being down shouldn't fail the collection
+	if err := i.sink.PublishDividends(*companyInfo, dividendHistory); err != nil {
+		i.mlog.Error(fmt.Sprintf("Error publishing dividend history for %s", ticker), err)
 	}
-	var bearer = "Bearer " + *token
+	return nil
+}
 
-	req.Header.Set("Authorization", bearer)
-	client := &http.Client{}
-	response, err = client.Do(req)
+// GetDividendHistory returns the locally stored dividend history of the provided company
+func (i *moneyControlService) GetDividendHistory(ticker string) ([]models.Dividend, error) {
+	companyInfo, err := i.moneycontrolRepository.ResolveCompany(ticker)
 	if err != nil {
-		i.mlog.Error(err)
-		return err
+		i.mlog.Error("Error fetching provided company", err)
+		return nil, err
 	}
-	defer response.Body.Close()
-	return nil
+	return i.moneycontrolRepository.FetchDividends(companyInfo.ID)
 }
 
 func (i *moneyControlService) CaptureHistoricalData(ticker string) error {
-	companyInfo, err := i.moneycontrolRepository.FetchCompanyByNameConstant(ticker)
+	companyInfo, err := i.moneycontrolRepository.ResolveCompany(ticker)
 	if err != nil {
 		i.mlog.Error("Error fetching provided company", err)
 		return err
 	}
-	url := fmt.Sprintf(i.cfg.MoneyControlHistoricalDataUrl, companyInfo.NSEID, fmt.Sprint(time.Now().Unix()))
+	url, err := i.historicalDataURL(*companyInfo)
+	if err != nil {
+		i.mlog.Error(err)
+		return err
+	}
 	response, err := http.Get(url)
 	if err != nil {
 		i.mlog.Error(err)
 		return err
 	}
+	defer response.Body.Close()
 	if response.StatusCode != iris.StatusOK {
+		err = fmt.Errorf("unexpected status %d fetching historical data for %s", response.StatusCode, ticker)
 		i.mlog.Error(err)
 		return err
 	}
-	defer response.Body.Close()
 
 	body, err := ioutil.ReadAll(response.Body)
 	if err != nil {
@@ -342,47 +532,93 @@ func (i *moneyControlService) CaptureHistoricalData(ticker string) error {
 			ticker), err)
 		return err
 	}
-	token, err := i.GetMoneyBSToken(i.cfg)
+	candles, err := parseCandles(body)
 	if err != nil {
-		i.mlog.Error("Error while generating token for MoneyBS", err)
+		i.mlog.Error(fmt.Sprintf("Failed to parse historical data for %s:", ticker), err)
 		return err
 	}
-	req, err := http.NewRequest("POST", fmt.Sprintf(i.cfg.MoneyBSBaseURL+i.cfg.MoneyBSHistoricalDataEndpoint, ticker), bytes.NewBuffer(body))
-	if err != nil {
-		i.mlog.Error("Error creating post request to MoneyBS", err)
+	if err := i.moneycontrolRepository.UpsertCandles(companyInfo.ID, candles); err != nil {
+		i.mlog.Error(fmt.Sprintf("Error saving historical data for %s", ticker), err)
 		return err
 	}
-	var bearer = "Bearer " + *token
-
-	req.Header.Set("Authorization", bearer)
-	client := &http.Client{}
-	go client.Do(req)
+	go func() {
+		if err := i.sink.PublishCandles(*companyInfo, candles); err != nil {
+			i.mlog.Error(fmt.Sprintf("Error publishing historical data for %s", ticker), err)
+		}
+	}()
 	return nil
 }
 
-func (i *moneyControlService) GetMoneyBSToken(cfg *config.AppEnvVars) (*string, error) {
-	req, err := http.NewRequest("GET", cfg.MoneyBSBaseURL+cfg.MoneyBSAuthEndpoint, nil)
-	if err != nil {
-		i.mlog.Error(err)
-		return nil, err
+// historicalDataURL returns the chart history URL of a company, using its BSE scrip code when it
+// isn't listed on NSE
+func (i *moneyControlService) historicalDataURL(companyInfo models.CompanyInfo) (string, error) {
+	now := fmt.Sprint(time.Now().Unix())
+	if companyInfo.NSEID != "" {
+		return fmt.Sprintf(i.cfg.MoneyControlHistoricalDataUrl, companyInfo.NSEID, now), nil
+	}
+	if companyInfo.BSEID == "" {
+		return "", fmt.Errorf("%s has neither an NSE ID nor a BSE ID", companyInfo.Symbol)
+	}
+	if i.cfg.MoneyControlBSEHistoricalDataUrl == "" {
+		// the NSE chart URL doesn't know BSE scrip codes
+		return "", fmt.Errorf("%s: %w", companyInfo.Symbol, ErrBSEHistoryNotConfigured)
+	}
+	return fmt.Sprintf(i.cfg.MoneyControlBSEHistoricalDataUrl, companyInfo.BSEID, now), nil
+}
+
+// exchangeID returns the NSE ID of a company, or its BSE scrip code when it isn't listed on NSE
+func exchangeID(companyInfo models.CompanyInfo) string {
+	if companyInfo.NSEID != "" {
+		return companyInfo.NSEID
 	}
-	req.Header.Add("x-api-key", cfg.MoneyBSAPIKey)
-	client := &http.Client{}
-	response, err := client.Do(req)
+	return companyInfo.BSEID
+}
+
+// GetHistoricalData returns the locally stored daily candles of the provided company between from and to,
+// a zero time leaving that side open
+func (i *moneyControlService) GetHistoricalData(ticker string, from, to time.Time) ([]models.Candle, error) {
+	companyInfo, err := i.moneycontrolRepository.ResolveCompany(ticker)
 	if err != nil {
-		i.mlog.Error(err)
+		i.mlog.Error("Error fetching provided company", err)
 		return nil, err
 	}
+	var fromUnix, toUnix int64
+	if !from.IsZero() {
+		fromUnix = from.Unix()
+	}
+	if !to.IsZero() {
+		toUnix = to.Unix()
+	}
+	return i.moneycontrolRepository.FetchCandles(companyInfo.ID, fromUnix, toUnix)
+}
 
-	defer response.Body.Close()
-	// Read response body
-	body, err := ioutil.ReadAll(response.Body)
-	if err != nil {
-		i.mlog.Error(err)
+// parseCandles converts the column oriented chart history response into candles
+func parseCandles(body []byte) ([]models.Candle, error) {
+	var history historicalDataJson
+	if err := json.Unmarshal(body, &history); err != nil {
 		return nil, err
 	}
-	b := string(body)
-	return &b, nil
+	if history.Status != "ok" {
+		// moneycontrol answers "no_data" for tickers without history
+		return nil, nil
+	}
+	n := len(history.Timestamp)
+	if len(history.Open) != n || len(history.High) != n || len(history.Low) != n ||
+		len(history.Close) != n || len(history.Volume) != n {
+		return nil, fmt.Errorf("historical data arrays have mismatched lengths")
+	}
+	candles := make([]models.Candle, 0, n)
+	for idx := 0; idx < n; idx++ {
+		candles = append(candles, models.Candle{
+			Timestamp: history.Timestamp[idx],
+			Open:      history.Open[idx],
+			High:      history.High[idx],
+			Low:       history.Low[idx],
+			Close:     history.Close[idx],
+			Volume:    history.Volume[idx],
+		})
+	}
+	return candles, nil
 }
 
 // getStockQuote creates and returns the web document from a web URL
@@ -392,6 +628,9 @@ func getStockQuote(URL string) (*goquery.Document, error) {
 		return nil, err
 	}
 	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return nil, fmt.Errorf("%s answered %s", URL, res.Status)
+	}
 	doc, err := goquery.NewDocumentFromReader(res.Body)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,40 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/johnsonabraham/moneycontrolscraper/internal/moneycontrol/models"
+)
+
+func TestParseCandles(t *testing.T) {
+	tests := []struct {
+		body    string
+		want    []models.Candle
+		wantErr bool
+	}{
+		{
+			`{"s":"ok","t":[1760932800,1761019200],"o":[3010.5,3022],"h":[3040,3051.25],"l":[3001,3015.1],"c":[3025.4,3048],"v":[1520300,987000]}`,
+			[]models.Candle{
+				{Timestamp: 1760932800, Open: 3010.5, High: 3040, Low: 3001, Close: 3025.4, Volume: 1520300},
+				{Timestamp: 1761019200, Open: 3022, High: 3051.25, Low: 3015.1, Close: 3048, Volume: 987000},
+			},
+			false,
+		},
+		{`{"s":"ok","t":[],"o":[],"h":[],"l":[],"c":[],"v":[]}`, []models.Candle{}, false},
+		{`{"s":"no_data"}`, nil, false},
+		{`{"s":"ok","t":[1760932800,1761019200],"o":[3010.5],"h":[3040],"l":[3001],"c":[3025.4],"v":[1520300]}`, nil, true},
+		{`{"s":"ok","t":[1760932800],"o":[3010.5],"h":[3040],"l":[3001],"c":[3025.4],"v":[]}`, nil, true},
+		{`<html>Service Unavailable</html>`, nil, true},
+	}
+	for _, tt := range tests {
+		got, err := parseCandles([]byte(tt.body))
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseCandles(%s) error = %v, wantErr %v", tt.body, err, tt.wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseCandles(%s) = %v, want %v", tt.body, got, tt.want)
+		}
+	}
+}
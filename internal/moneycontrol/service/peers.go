@@ -0,0 +1,104 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/johnsonabraham/moneycontrolscraper/internal/moneycontrol/models"
+)
+
+const (
+	defaultPeerLimit = 20
+	maxPeerLimit     = 100
+)
+
+// ErrNoSubSector is returned when peers are requested for a company whose sub-sector isn't known,
+// which is the case until the company is enriched
+var ErrNoSubSector = errors.New("company has no sub-sector")
+
+// GetPeers returns the largest listed companies of the sub-sector of a company by market cap with
+// their latest stored close and key ratios. The company itself is always included, after the others
+// when it doesn't rank within limit.
+func (i *moneyControlService) GetPeers(ticker string, limit int) (models.PeerGroup, error) {
+	companyInfo, err := i.moneycontrolRepository.ResolveCompany(ticker)
+	if err != nil {
+		i.mlog.Error("Error fetching provided company", err)
+		return models.PeerGroup{}, err
+	}
+	if companyInfo.SubSectorDetails == "" {
+		return models.PeerGroup{}, ErrNoSubSector
+	}
+	if limit <= 0 {
+		limit = defaultPeerLimit
+	}
+	if limit > maxPeerLimit {
+		limit = maxPeerLimit
+	}
+	listed, err := i.moneycontrolRepository.FetchSubSectorCompanies(companyInfo.SubSectorDetails)
+	if err != nil {
+		return models.PeerGroup{}, err
+	}
+	ranks := make(map[int64]int, len(listed))
+	for idx, company := range listed {
+		ranks[company.ID] = idx + 1
+	}
+	companies := listed
+	if len(companies) > limit {
+		companies = companies[:limit]
+	}
+	// a delisted company isn't among the listed ones, so it is added unranked
+	if rank, found := ranks[companyInfo.ID]; !found || rank > limit {
+		companies = append(companies[:len(companies):len(companies)], *companyInfo)
+	}
+	companyIDs := make([]int64, len(companies))
+	for idx, company := range companies {
+		companyIDs[idx] = company.ID
+	}
+	candles, err := i.moneycontrolRepository.FetchLatestCandles(companyIDs)
+	if err != nil {
+		return models.PeerGroup{}, err
+	}
+
+	peers := make([]models.Peer, len(companies))
+	for idx, company := range companies {
+		peers[idx] = models.Peer{
+			Rank:        ranks[company.ID],
+			Self:        company.ID == companyInfo.ID,
+			ID:          company.ID,
+			Company:     company.Company,
+			CompanyName: company.CompanyName,
+			NSEID:       company.NSEID,
+			BSEID:       company.BSEID,
+			MarketCap:   company.MarketCap,
+			PE:          company.PE,
+			IndustryPE:  company.IndustryPE,
+			BookValue:   company.BookValue,
+			Week52High:  company.Week52High,
+			Week52Low:   company.Week52Low,
+		}
+		if candle, found := candles[company.ID]; found {
+			price, priceAt := candle.Close, candle.Timestamp
+			peers[idx].Price, peers[idx].PriceAt = &price, &priceAt
+			if company.BookValue > 0 {
+				priceToBook := price / company.BookValue
+				peers[idx].PriceToBook = &priceToBook
+			}
+		}
+	}
+	return models.PeerGroup{
+		SubSector: companyInfo.SubSectorDetails,
+		Total:     int64(len(listed)),
+		Peers:     peers,
+	}, nil
+}
+
+// GetSectorSummaries returns the company count and total market cap of every sector
+func (i *moneyControlService) GetSectorSummaries() ([]models.SectorSummary, error) {
+	summaries, err := i.moneycontrolRepository.FetchSectorSummaries()
+	if err != nil {
+		return nil, err
+	}
+	if summaries == nil {
+		summaries = []models.SectorSummary{}
+	}
+	return summaries, nil
+}
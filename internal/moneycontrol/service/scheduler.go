@@ -0,0 +1,164 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/johnsonabraham/moneycontrolscraper/internal/moneycontrol/models"
+	"github.com/johnsonabraham/moneycontrolscraper/pkg/cron"
+	"gorm.io/gorm"
+)
+
+const (
+	ScheduleSymbols    = "symbols"
+	ScheduleDividends  = "dividends"
+	ScheduleHistorical = "historical"
+)
+
+// scheduleEntry is a configured recurring scrape. Specs are cron expressions evaluated in IST,
+// so "30 16 * * 1-5" runs after the NSE close on weekdays. Entries limited to trading days also skip
+// the holidays of the market calendar.
+type scheduleEntry struct {
+	name            string
+	spec            string
+	schedule        *cron.Schedule
+	tradingDaysOnly bool
+	enqueue         func() (*models.Job, error)
+}
+
+// StartScheduler parses the SCHEDULE_* specs and triggers the matching scrapes in the background.
+// Empty specs leave that scrape unscheduled.
+func (i *moneyControlService) StartScheduler() error {
+	specs := []struct {
+		name            string
+		spec            string
+		tradingDaysOnly bool
+		enqueue         func() (*models.Job, error)
+	}{
+		{ScheduleSymbols, i.cfg.ScheduleSymbols, false, i.EnqueueSymbolCollection},
+		{ScheduleDividends, i.cfg.ScheduleDividends, false, func() (*models.Job, error) {
+			return i.EnqueueDividendsBulk(models.BulkSelection{All: true})
+		}},
+		{ScheduleHistorical, i.cfg.ScheduleHistorical, true, i.EnqueueBackfill},
+	}
+	for _, spec := range specs {
+		if spec.spec == "" {
+			continue
+		}
+		schedule, err := cron.Parse(spec.spec)
+		if err != nil {
+			return fmt.Errorf("schedule %s: %w", spec.name, err)
+		}
+		i.schedules = append(i.schedules, &scheduleEntry{
+			name:            spec.name,
+			spec:            spec.spec,
+			schedule:        schedule,
+			tradingDaysOnly: spec.tradingDaysOnly,
+			enqueue:         spec.enqueue,
+		})
+	}
+	if len(i.schedules) == 0 {
+		return nil
+	}
+	go i.runScheduler()
+	return nil
+}
+
+func (i *moneyControlService) runScheduler() {
+	// wake up at the start of every minute
+	now := time.Now()
+	time.Sleep(now.Truncate(time.Minute).Add(time.Minute).Sub(now))
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	for {
+		minute := time.Now().In(MarketLocation).Round(time.Minute)
+		for _, entry := range i.schedules {
+			if entry.schedule.Matches(minute) {
+				i.trigger(entry, minute)
+			}
+		}
+		<-ticker.C
+	}
+}
+
+// trigger queues the scrape of a schedule unless its previous job is still queued or running
+func (i *moneyControlService) trigger(entry *scheduleEntry, at time.Time) {
+	run := &models.ScheduleRun{Name: entry.name, TriggeredAt: at}
+	if entry.tradingDaysOnly && !i.calendar.IsTradingDay(at) {
+		run.Outcome = models.ScheduleSkipped
+		run.Error = "not an NSE trading day"
+	} else if busy, jobID := i.scheduleBusy(entry.name); busy {
+		run.Outcome = models.ScheduleSkipped
+		run.JobID = jobID
+		run.Error = fmt.Sprintf("job %d is still in progress", jobID)
+	} else if job, err := entry.enqueue(); err != nil {
+		run.Outcome = models.ScheduleSkipped
+		if !errors.Is(err, ErrBackfillRunning) {
+			run.Outcome = models.ScheduleFailed
+		}
+		run.Error = err.Error()
+	} else {
+		run.Outcome = models.ScheduleTriggered
+		run.JobID = job.ID
+	}
+	i.mlog.Info(fmt.Sprintf("Schedule %s %s", entry.name, run.Outcome))
+	if err := i.jobs.RecordScheduleRun(run); err != nil {
+		i.mlog.Error(fmt.Sprintf("Error recording run of schedule %s", entry.name), err)
+	}
+}
+
+// scheduleBusy reports whether the job last queued by a schedule hasn't finished. Firings skipped in
+// between, for a busy job or a holiday, don't hide it.
+func (i *moneyControlService) scheduleBusy(name string) (bool, int64) {
+	last, err := i.jobs.LatestScheduleRunWithJob(name)
+	if err != nil {
+		return false, 0
+	}
+	job, err := i.jobs.Get(last.JobID)
+	if err != nil {
+		return false, 0
+	}
+	return job.State == models.JobQueued || job.State == models.JobRunning, job.ID
+}
+
+// GetSchedules returns every configured schedule with its next and last run
+func (i *moneyControlService) GetSchedules() ([]models.ScheduleStatus, error) {
+	statuses := make([]models.ScheduleStatus, 0, len(i.schedules))
+	now := time.Now().In(MarketLocation)
+	for _, entry := range i.schedules {
+		status := models.ScheduleStatus{
+			Name:    entry.name,
+			Spec:    entry.spec,
+			NextRun: i.nextRun(entry, now),
+		}
+		last, err := i.jobs.LatestScheduleRun(entry.name)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		status.LastRun = last
+		if last != nil && last.JobID != 0 {
+			if job, err := i.jobs.Get(last.JobID); err == nil {
+				status.LastJob = job
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// nextRun returns the first firing of a schedule after now that isn't skipped for falling outside
+// the trading days, or the zero time when there is none within a year
+func (i *moneyControlService) nextRun(entry *scheduleEntry, now time.Time) time.Time {
+	limit := now.AddDate(1, 0, 0)
+	next := entry.schedule.Next(now)
+	for entry.tradingDaysOnly && !next.IsZero() && !i.calendar.IsTradingDay(next) {
+		if next.After(limit) {
+			return time.Time{}
+		}
+		// the rest of a skipped day is skipped too
+		day := time.Date(next.Year(), next.Month(), next.Day(), 23, 59, 0, 0, next.Location())
+		next = entry.schedule.Next(day)
+	}
+	return next
+}
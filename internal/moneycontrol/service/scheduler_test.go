@@ -0,0 +1,122 @@
+package service
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/johnsonabraham/moneycontrolscraper/config"
+	"github.com/johnsonabraham/moneycontrolscraper/internal/moneycontrol/models"
+	"github.com/kataras/golog"
+	"gorm.io/gorm"
+)
+
+// memoryJobRepository keeps jobs and schedule runs in memory
+type memoryJobRepository struct {
+	mu   sync.Mutex
+	jobs []models.Job
+	runs []models.ScheduleRun
+}
+
+func (m *memoryJobRepository) CreateJob(job *models.Job) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job.ID = int64(len(m.jobs) + 1)
+	m.jobs = append(m.jobs, *job)
+	return nil
+}
+
+func (m *memoryJobRepository) SaveJob(job *models.Job) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.jobs[job.ID-1] = *job
+	return nil
+}
+
+func (m *memoryJobRepository) FetchJob(id int64) (*models.Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if id < 1 || int(id) > len(m.jobs) {
+		return nil, gorm.ErrRecordNotFound
+	}
+	job := m.jobs[id-1]
+	return &job, nil
+}
+
+func (m *memoryJobRepository) FetchJobs(models.JobFilter) ([]models.Job, error) {
+	return m.jobs, nil
+}
+
+func (m *memoryJobRepository) FailUnfinishedJobs(string) error { return nil }
+
+func (m *memoryJobRepository) CreateScheduleRun(run *models.ScheduleRun) error {
+	run.ID = int64(len(m.runs) + 1)
+	m.runs = append(m.runs, *run)
+	return nil
+}
+
+func (m *memoryJobRepository) FetchLatestScheduleRun(name string) (*models.ScheduleRun, error) {
+	return m.latestRun(name, false)
+}
+
+func (m *memoryJobRepository) FetchLatestScheduleRunWithJob(name string) (*models.ScheduleRun, error) {
+	return m.latestRun(name, true)
+}
+
+func (m *memoryJobRepository) latestRun(name string, withJob bool) (*models.ScheduleRun, error) {
+	for idx := len(m.runs) - 1; idx >= 0; idx-- {
+		if m.runs[idx].Name == name && (!withJob || m.runs[idx].JobID != 0) {
+			run := m.runs[idx]
+			return &run, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func TestTriggerSkipsWhileJobRuns(t *testing.T) {
+	repo := &memoryJobRepository{}
+	calendar, _ := NewMarketCalendar("2026-10-20")
+	svc := &moneyControlService{
+		mlog:     golog.New(),
+		jobs:     NewJobRunner(golog.New(), &config.AppEnvVars{}, repo),
+		calendar: calendar,
+	}
+	entry := &scheduleEntry{
+		name:            ScheduleHistorical,
+		tradingDaysOnly: true,
+		enqueue: func() (*models.Job, error) {
+			// the job keeps running for the whole test
+			job := &models.Job{State: models.JobRunning}
+			return job, repo.CreateJob(job)
+		},
+	}
+	// 2026-10-19 is a Monday and 2026-10-20 a holiday
+	monday := time.Date(2026, 10, 19, 16, 30, 0, 0, MarketLocation)
+	firings := []struct {
+		at      time.Time
+		outcome string
+		jobID   int64
+	}{
+		{monday, models.ScheduleTriggered, 1},
+		{monday.Add(time.Minute), models.ScheduleSkipped, 1},
+		{monday.Add(2 * time.Minute), models.ScheduleSkipped, 1},
+		{monday.AddDate(0, 0, 1), models.ScheduleSkipped, 0},
+		{monday.AddDate(0, 0, 2), models.ScheduleSkipped, 1},
+	}
+	for idx, firing := range firings {
+		svc.trigger(entry, firing.at)
+		run := repo.runs[len(repo.runs)-1]
+		if run.Outcome != firing.outcome || run.JobID != firing.jobID {
+			t.Errorf("firing %d: outcome %s job %d, want %s job %d", idx, run.Outcome, run.JobID, firing.outcome, firing.jobID)
+		}
+	}
+	if len(repo.jobs) != 1 {
+		t.Errorf("queued %d jobs, want 1", len(repo.jobs))
+	}
+
+	repo.jobs[0].State = models.JobSucceeded
+	svc.trigger(entry, monday.AddDate(0, 0, 3))
+	if run := repo.runs[len(repo.runs)-1]; run.Outcome != models.ScheduleTriggered || run.JobID != 2 {
+		t.Errorf("firing after the job finished: outcome %s job %d, want triggered job 2", run.Outcome, run.JobID)
+	}
+}
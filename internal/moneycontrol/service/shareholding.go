@@ -0,0 +1,186 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/johnsonabraham/moneycontrolscraper/internal/moneycontrol/models"
+)
+
+// nonSlug matches the characters dropped when an unknown holder label becomes a category
+var nonSlug = regexp.MustCompile(`[^a-z0-9]+`)
+
+// holderCategory maps a row label of the shareholding pattern table to a holder category.
+// Labels of no known category are kept as a lowercase slug.
+func holderCategory(label string) string {
+	lower := strings.ToLower(label)
+	switch {
+	case strings.Contains(lower, "pledge"):
+		return models.HolderPromoterPledge
+	case strings.Contains(lower, "promoter") && !strings.Contains(lower, "non"):
+		return models.HolderPromoter
+	case strings.Contains(lower, "mutual fund"):
+		return models.HolderMutualFunds
+	case strings.Contains(lower, "fii"), strings.Contains(lower, "fpi"), strings.Contains(lower, "foreign institution"):
+		return models.HolderFII
+	case strings.Contains(lower, "dii"), strings.Contains(lower, "domestic institution"):
+		return models.HolderDII
+	case strings.Contains(lower, "retail"), strings.Contains(lower, "public"), strings.Contains(lower, "individual"):
+		return models.HolderRetail
+	case strings.Contains(lower, "other"):
+		return models.HolderOthers
+	}
+	return strings.Trim(nonSlug.ReplaceAllString(lower, "_"), "_")
+}
+
+// ScrapeShareholding captures the quarterly shareholding pattern of a company
+func (i *moneyControlService) ScrapeShareholding(ticker string) error {
+	companyInfo, err := i.moneycontrolRepository.ResolveCompany(ticker)
+	if err != nil {
+		i.mlog.Error("Error fetching provided company", err)
+		return err
+	}
+	response, err := http.Get(fmt.Sprintf(i.cfg.MoneyControlShareholdingURL, companyInfo.CompanyName, companyInfo.Symbol))
+	if err != nil {
+		i.mlog.Error(fmt.Sprintf("Error while scraping shareholding pattern for %s", ticker), err)
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("shareholding pattern page answered %s", response.Status)
+	}
+
+	doc, err := goquery.NewDocumentFromReader(response.Body)
+	if err != nil {
+		i.mlog.Error(fmt.Sprintf("Error parsing shareholding pattern page for %s", ticker), err)
+		return err
+	}
+	holdings, err := parseShareholding(doc)
+	if err != nil {
+		i.mlog.Error(fmt.Sprintf("Error reading shareholding pattern for %s", ticker), err)
+		return err
+	}
+	if err := i.moneycontrolRepository.UpsertShareholdings(companyInfo.ID, holdings); err != nil {
+		i.mlog.Error(fmt.Sprintf("Error saving shareholding pattern for %s", ticker), err)
+		return err
+	}
+	return nil
+}
+
+// parseShareholding reads the shareholding pattern table, the first row naming quarters being the
+// header. Several rows of one category, such as the sub categories of institutions, keep the first.
+func parseShareholding(doc *goquery.Document) ([]models.Shareholding, error) {
+	var quarters []string
+	var holdings []models.Shareholding
+	seen := make(map[string]bool)
+	doc.Find("table.mctable1 tr").Each(func(_ int, row *goquery.Selection) {
+		cells := row.Find("td, th")
+		if cells.Length() < 2 {
+			return
+		}
+		values := cells.Slice(1, goquery.ToEnd)
+		if quarters == nil {
+			header := make([]string, values.Length())
+			found := false
+			values.Each(func(idx int, cell *goquery.Selection) {
+				if quarter, ok := parsePeriod(cell.Text()); ok {
+					header[idx] = quarter
+					found = true
+				}
+			})
+			if found {
+				quarters = header
+			}
+			return
+		}
+
+		label := strings.Join(strings.Fields(cells.First().Text()), " ")
+		category := holderCategory(label)
+		if category == "" || seen[category] {
+			return
+		}
+		var rowHoldings []models.Shareholding
+		values.Each(func(idx int, cell *goquery.Selection) {
+			if idx >= len(quarters) || quarters[idx] == "" {
+				return
+			}
+			percentage, ok := jsonNumber(strings.TrimSuffix(strings.TrimSpace(cell.Text()), "%"))
+			if !ok {
+				return
+			}
+			rowHoldings = append(rowHoldings, models.Shareholding{Quarter: quarters[idx], Category: category, Percentage: percentage})
+		})
+		if len(rowHoldings) > 0 {
+			seen[category] = true
+			holdings = append(holdings, rowHoldings...)
+		}
+	})
+	if quarters == nil {
+		return nil, fmt.Errorf("no shareholding pattern table found")
+	}
+	return holdings, nil
+}
+
+// EnqueueShareholdingCollection queues a job scraping the shareholding pattern of a company
+func (i *moneyControlService) EnqueueShareholdingCollection(ticker string) (*models.Job, error) {
+	if _, err := i.moneycontrolRepository.ResolveCompany(ticker); err != nil {
+		return nil, err
+	}
+	return i.jobs.Enqueue(models.JobCollectShareholding, ticker, func(progress *JobProgress) error {
+		progress.SetTotal(1)
+		err := i.ScrapeShareholding(ticker)
+		progress.Step(err)
+		return err
+	})
+}
+
+// GetShareholding returns the stored shareholding pattern of a company newest quarter first, with
+// the change of every category since the quarter before
+func (i *moneyControlService) GetShareholding(ticker string) ([]models.ShareholdingQuarter, error) {
+	companyInfo, err := i.moneycontrolRepository.ResolveCompany(ticker)
+	if err != nil {
+		i.mlog.Error("Error fetching provided company", err)
+		return nil, err
+	}
+	holdings, err := i.moneycontrolRepository.FetchShareholdings(companyInfo.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	quarters := []models.ShareholdingQuarter{}
+	for _, holding := range holdings {
+		if len(quarters) == 0 || quarters[len(quarters)-1].Quarter != holding.Quarter {
+			quarters = append(quarters, models.ShareholdingQuarter{
+				Quarter:  holding.Quarter,
+				Holdings: make(map[string]float64),
+			})
+		}
+		quarter := &quarters[len(quarters)-1]
+		if holding.Category == models.HolderPromoterPledge {
+			pledge := holding.Percentage
+			quarter.PromoterPledge = &pledge
+			continue
+		}
+		quarter.Holdings[holding.Category] = holding.Percentage
+	}
+	for idx := 1; idx < len(quarters); idx++ {
+		previous, quarter := quarters[idx-1], &quarters[idx]
+		quarter.Changes = make(map[string]float64)
+		for category, percentage := range quarter.Holdings {
+			if before, found := previous.Holdings[category]; found {
+				// round away float noise such as 0.30000000000000004
+				quarter.Changes[category] = math.Round((percentage-before)*100) / 100
+			}
+		}
+	}
+
+	// newest first
+	for left, right := 0, len(quarters)-1; left < right; left, right = left+1, right-1 {
+		quarters[left], quarters[right] = quarters[right], quarters[left]
+	}
+	return quarters, nil
+}
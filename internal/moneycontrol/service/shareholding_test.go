@@ -0,0 +1,93 @@
+package service
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/johnsonabraham/moneycontrolscraper/internal/moneycontrol/models"
+)
+
+func TestHolderCategory(t *testing.T) {
+	tests := []struct {
+		label string
+		want  string
+	}{
+		{"Promoter & Promoter Group", models.HolderPromoter},
+		{"Pledged Promoter Holding", models.HolderPromoterPledge},
+		{"Mutual Funds", models.HolderMutualFunds},
+		{"FII", models.HolderFII},
+		{"FPI (Category I)", models.HolderFII},
+		{"Foreign Institutions", models.HolderFII},
+		{"DII", models.HolderDII},
+		{"Domestic Institutions", models.HolderDII},
+		{"Retail & Others", models.HolderRetail},
+		{"Public", models.HolderRetail},
+		{"Individuals holding up to Rs. 2 lakh", models.HolderRetail},
+		{"Others", models.HolderOthers},
+		{"Non Promoter", "non_promoter"},
+		{"Insurance Companies (LIC)", "insurance_companies_lic"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := holderCategory(tt.label); got != tt.want {
+			t.Errorf("holderCategory(%q) = %q, want %q", tt.label, got, tt.want)
+		}
+	}
+}
+
+func TestParseShareholding(t *testing.T) {
+	tests := []struct {
+		html    string
+		want    []models.Shareholding
+		wantErr bool
+	}{
+		{
+			`<table class="mctable1">
+			  <tr><th>Holder's Name</th><th>Sep 2024</th><th>Jun 2024</th><th>Change</th></tr>
+			  <tr><td>Promoter</td><td>72.30%</td><td>72.41 %</td><td>-0.11</td></tr>
+			  <tr><td>FII</td><td>12.50%</td><td>-</td><td></td></tr>
+			  <tr><td>Mutual Funds</td><td>4.10%</td><td>3.95%</td><td></td></tr>
+			  <tr><td>Mutual Funds - Equity</td><td>3.00%</td><td>2.90%</td><td></td></tr>
+			  <tr><td>Retail &amp; Others</td><td>11.10%</td><td>11.20%</td><td></td></tr>
+			  <tr><td>Pledged Promoter Holding</td><td>0.00%</td><td>1.25%</td><td></td></tr>
+			  <tr><td>Total</td><td></td><td></td><td></td></tr>
+			</table>`,
+			[]models.Shareholding{
+				{Quarter: "2024-09", Category: models.HolderPromoter, Percentage: 72.3},
+				{Quarter: "2024-06", Category: models.HolderPromoter, Percentage: 72.41},
+				{Quarter: "2024-09", Category: models.HolderFII, Percentage: 12.5},
+				{Quarter: "2024-09", Category: models.HolderMutualFunds, Percentage: 4.1},
+				{Quarter: "2024-06", Category: models.HolderMutualFunds, Percentage: 3.95},
+				{Quarter: "2024-09", Category: models.HolderRetail, Percentage: 11.1},
+				{Quarter: "2024-06", Category: models.HolderRetail, Percentage: 11.2},
+				{Quarter: "2024-09", Category: models.HolderPromoterPledge, Percentage: 0},
+				{Quarter: "2024-06", Category: models.HolderPromoterPledge, Percentage: 1.25},
+			},
+			false,
+		},
+		{
+			`<table class="mctable1">
+			  <tr><td>Promoter</td><td>72.30%</td></tr>
+			</table>`,
+			nil,
+			true,
+		},
+		{`<p>Shareholding pattern not available</p>`, nil, true},
+	}
+	for _, tt := range tests {
+		doc, err := goquery.NewDocumentFromReader(strings.NewReader(tt.html))
+		if err != nil {
+			t.Fatalf("invalid fixture: %v", err)
+		}
+		got, err := parseShareholding(doc)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseShareholding(%s) error = %v, wantErr %v", tt.html, err, tt.wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseShareholding(%s) = %+v, want %+v", tt.html, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/johnsonabraham/moneycontrolscraper/config"
+	"github.com/johnsonabraham/moneycontrolscraper/internal/moneycontrol/models"
+	"github.com/kataras/golog"
+)
+
+const (
+	SinkMoneyBS = "moneybs"
+	SinkLocal   = "local"
+)
+
+// Sink receives scraped data for downstream consumers once it is stored locally
+type Sink interface {
+	PublishDividends(companyInfo models.CompanyInfo, dividends []models.Dividend) error
+	PublishCandles(companyInfo models.CompanyInfo, candles []models.Candle) error
+	PublishCompanyInfo(companyInfo models.CompanyInfo) error
+}
+
+// NewSink returns the sink selected by cfg.Sink. Without SINK, deployments configuring
+// MONEYBS_BASE_URL keep publishing to MoneyBS as they did before sinks could be chosen, and the
+// others store locally only. The MoneyBS sink needs its base URL, auth and data endpoints configured.
+func NewSink(mlog *golog.Logger, cfg *config.AppEnvVars) (Sink, error) {
+	sink := cfg.Sink
+	if sink == "" {
+		sink = SinkLocal
+		if cfg.MoneyBSBaseURL != "" {
+			sink = SinkMoneyBS
+		}
+		mlog.Info(fmt.Sprintf("SINK not set, publishing to the %s sink", sink))
+	}
+	switch sink {
+	case SinkMoneyBS:
+		if cfg.MoneyBSBaseURL == "" || cfg.MoneyBSAuthEndpoint == "" || cfg.MoneyBSHistoricalDataEndpoint == "" ||
+			cfg.MoneyBSHistoricalDividendDataEndpoint == "" {
+			return nil, fmt.Errorf("sink %s needs MONEYBS_BASE_URL, MONEYBS_AUTH_ENDPOINT, "+
+				"MONEYBS_HISTORICAL_DATA_ENDPOINT and MONEYBS_HISTORICAL_DIVIDEND_DATA_ENDPOINT", SinkMoneyBS)
+		}
+		client := &http.Client{}
+		return &moneyBSSink{
+			mlog:   mlog,
+			cfg:    cfg,
+			client: client,
+			tokens: newMoneyBSTokenManager(mlog, cfg, client),
+		}, nil
+	case SinkLocal:
+		return localSink{}, nil
+	default:
+		return nil, fmt.Errorf("unknown sink %q, expected %s or %s", cfg.Sink, SinkMoneyBS, SinkLocal)
+	}
+}
+
+// localSink publishes nowhere, the data already being stored in the local database
+type localSink struct{}
+
+func (localSink) PublishDividends(models.CompanyInfo, []models.Dividend) error { return nil }
+
+func (localSink) PublishCandles(models.CompanyInfo, []models.Candle) error { return nil }
+
+func (localSink) PublishCompanyInfo(models.CompanyInfo) error { return nil }
+
+// moneyBSSink posts scraped data to the MoneyBS service
+type moneyBSSink struct {
+	mlog   *golog.Logger
+	cfg    *config.AppEnvVars
+	client *http.Client
+	tokens *moneyBSTokenManager
+}
+
+func (m *moneyBSSink) PublishDividends(companyInfo models.CompanyInfo, dividends []models.Dividend) error {
+	return m.post(fmt.Sprintf(m.cfg.MoneyBSHistoricalDividendDataEndpoint, exchangeID(companyInfo)), dividends)
+}
+
+// PublishCandles posts candles in the column oriented moneycontrol chart format MoneyBS consumes
+func (m *moneyBSSink) PublishCandles(companyInfo models.CompanyInfo, candles []models.Candle) error {
+	history := historicalDataJson{
+		Status:    "ok",
+		Timestamp: make([]int64, 0, len(candles)),
+		Open:      make([]float64, 0, len(candles)),
+		High:      make([]float64, 0, len(candles)),
+		Low:       make([]float64, 0, len(candles)),
+		Close:     make([]float64, 0, len(candles)),
+		Volume:    make([]int64, 0, len(candles)),
+	}
+	for _, candle := range candles {
+		history.Timestamp = append(history.Timestamp, candle.Timestamp)
+		history.Open = append(history.Open, candle.Open)
+		history.High = append(history.High, candle.High)
+		history.Low = append(history.Low, candle.Low)
+		history.Close = append(history.Close, candle.Close)
+		history.Volume = append(history.Volume, candle.Volume)
+	}
+	return m.post(fmt.Sprintf(m.cfg.MoneyBSHistoricalDataEndpoint, exchangeID(companyInfo)), history)
+}
+
+// PublishCompanyInfo posts company details when MONEYBS_COMPANY_INFO_ENDPOINT is configured
+func (m *moneyBSSink) PublishCompanyInfo(companyInfo models.CompanyInfo) error {
+	if m.cfg.MoneyBSCompanyInfoEndpoint == "" {
+		return nil
+	}
+	return m.post(fmt.Sprintf(m.cfg.MoneyBSCompanyInfoEndpoint, companyInfo.Symbol), companyInfo)
+}
+
+// post sends the payload to MoneyBS, refreshing the token and retrying once on a 401
+func (m *moneyBSSink) post(endpoint string, payload interface{}) error {
+	jsonBody, err := json.Marshal(payload)
+	if err != nil {
+		m.mlog.Error("Error while marshalling MoneyBS payload", err)
+		return err
+	}
+	for attempt := 0; ; attempt++ {
+		token, err := m.tokens.Token()
+		if err != nil {
+			m.mlog.Error("Error while generating token for MoneyBS", err)
+			return err
+		}
+		req, err := http.NewRequest("POST", m.cfg.MoneyBSBaseURL+endpoint, bytes.NewBuffer(jsonBody))
+		if err != nil {
+			m.mlog.Error("Error creating post request to MoneyBS", err)
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+		response, err := m.client.Do(req)
+		if err != nil {
+			m.mlog.Error(err)
+			return err
+		}
+		response.Body.Close()
+		if response.StatusCode == http.StatusUnauthorized && attempt == 0 {
+			m.tokens.Invalidate()
+			continue
+		}
+		if response.StatusCode < 200 || response.StatusCode > 299 {
+			return fmt.Errorf("MoneyBS responded with status %d for %s", response.StatusCode, endpoint)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/johnsonabraham/moneycontrolscraper/internal/moneycontrol/models"
+	"gorm.io/gorm"
+)
+
+// symbolCache keeps the ticker to moneycontrol URL mapping in memory so quote lookups
+// don't hit the database on every call. Every company is indexed under its NSE ID,
+// BSE ID, moneycontrol symbol and lowercase company name.
+type symbolCache struct {
+	mu     sync.RWMutex
+	loaded bool
+	stocks models.StocksInfo
+}
+
+func newSymbolCache() *symbolCache {
+	return &symbolCache{stocks: make(models.StocksInfo)}
+}
+
+func (c *symbolCache) get(ticker string) (models.StockURLValue, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	val, found := c.stocks[strings.ToLower(ticker)]
+	return val, found
+}
+
+func (c *symbolCache) isLoaded() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.loaded
+}
+
+func (c *symbolCache) add(company models.CompanyInfo) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.put(c.stocks, company)
+}
+
+func (c *symbolCache) replace(companies []models.CompanyInfo) {
+	stocks := make(models.StocksInfo, len(companies))
+	for _, company := range companies {
+		c.put(stocks, company)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stocks = stocks
+	c.loaded = true
+}
+
+func (c *symbolCache) put(stocks models.StocksInfo, company models.CompanyInfo) {
+	val := models.StockURLValue{
+		Sector:  company.Sector,
+		Company: company.CompanyName,
+		Symbol:  company.Symbol,
+	}
+	for _, key := range []string{company.NSEID, company.BSEID, company.Symbol, company.Company} {
+		if key != "" {
+			stocks[strings.ToLower(key)] = val
+		}
+	}
+}
+
+// refreshSymbolCache reloads the ticker cache from the company_infos table
+func (i *moneyControlService) refreshSymbolCache() error {
+	companies, err := i.moneycontrolRepository.FetchCompanies()
+	if err != nil {
+		i.mlog.Error("Error refreshing symbol cache", err)
+		return err
+	}
+	i.symbols.replace(companies)
+	i.mlog.Info("Symbol cache refreshed")
+	return nil
+}
+
+// resolveStock returns the URL details of a ticker, loading the cache on first use and
+// falling back to the database for tickers enriched since the last refresh
+func (i *moneyControlService) resolveStock(ticker string) (models.StockURLValue, error) {
+	if !i.symbols.isLoaded() {
+		if err := i.refreshSymbolCache(); err != nil {
+			return models.StockURLValue{}, err
+		}
+	}
+	if val, found := i.symbols.get(ticker); found {
+		return val, nil
+	}
+	company, err := i.moneycontrolRepository.ResolveCompany(ticker)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.StockURLValue{}, ErrCompanyNotFound
+		}
+		return models.StockURLValue{}, err
+	}
+	i.symbols.add(*company)
+	return models.StockURLValue{
+		Sector:  company.Sector,
+		Company: company.CompanyName,
+		Symbol:  company.Symbol,
+	}, nil
+}
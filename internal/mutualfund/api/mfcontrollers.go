@@ -0,0 +1,137 @@
+package mutualfundapi
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/johnsonabraham/moneycontrolscraper/config"
+	mcmodels "github.com/johnsonabraham/moneycontrolscraper/internal/moneycontrol/models"
+	mcservice "github.com/johnsonabraham/moneycontrolscraper/internal/moneycontrol/service"
+	"github.com/johnsonabraham/moneycontrolscraper/internal/mutualfund/models"
+	service "github.com/johnsonabraham/moneycontrolscraper/internal/mutualfund/service"
+	"github.com/johnsonabraham/moneycontrolscraper/pkg/params"
+	"github.com/kataras/golog"
+	"github.com/kataras/iris/v12"
+	"gorm.io/gorm"
+)
+
+type MutualFundHandler struct {
+	mutualFundService service.MutualFundService
+	mlog              *golog.Logger
+	cfg               *config.AppEnvVars
+}
+
+func NewMutualFundHandler(service service.MutualFundService, vlog *golog.Logger, cfg *config.AppEnvVars) *MutualFundHandler {
+	return &MutualFundHandler{
+		mutualFundService: service,
+		mlog:              vlog,
+		cfg:               cfg,
+	}
+}
+
+// CollectSchemes queues a refresh of the mutual fund scheme master
+func (h *MutualFundHandler) CollectSchemes(ctx iris.Context) {
+	job, err := h.mutualFundService.EnqueueSchemeCollection()
+	if err != nil {
+		if h.notConfigured(ctx, err) {
+			return
+		}
+		h.mlog.Error("Error queueing scheme collection", err)
+		ctx.StopWithJSON(iris.StatusInternalServerError, mcmodels.FailedResponse{
+			Status:   iris.StatusInternalServerError,
+			ErrorMsg: "Something went wrong, please try again after some time",
+		})
+		return
+	}
+	ctx.StatusCode(iris.StatusAccepted)
+	ctx.JSON(job)
+}
+
+// CollectNAVHistory queues a scrape of the NAV history of scheme
+func (h *MutualFundHandler) CollectNAVHistory(ctx iris.Context) {
+	code := ctx.URLParam("scheme")
+
+	job, err := h.mutualFundService.EnqueueNAVCollection(code)
+	if err != nil {
+		h.schemeFailed(ctx, code, err)
+		return
+	}
+	h.mlog.Info(fmt.Sprintf("NAV history collection for scheme %s queued as job %d", code, job.ID))
+
+	ctx.StatusCode(iris.StatusAccepted)
+	ctx.JSON(job)
+}
+
+// ListSchemes returns a page of the scheme master, q searching scheme names and codes
+func (h *MutualFundHandler) ListSchemes(ctx iris.Context) {
+	filter := models.SchemeFilter{
+		Query:    ctx.URLParam("q"),
+		Page:     ctx.URLParamIntDefault("page", 1),
+		PageSize: ctx.URLParamIntDefault("page_size", 0),
+	}
+	filter.IncludeDelisted, _ = ctx.URLParamBool("include_delisted")
+
+	page, err := h.mutualFundService.SearchSchemes(filter)
+	if err != nil {
+		h.mlog.Error("Error listing schemes", err)
+		ctx.StopWithJSON(iris.StatusInternalServerError, mcmodels.FailedResponse{
+			Status:   iris.StatusInternalServerError,
+			ErrorMsg: "Something went wrong, please try again after some time",
+		})
+		return
+	}
+	ctx.StatusCode(iris.StatusOK)
+	ctx.JSON(page)
+}
+
+// GetNAVHistory returns the stored NAVs of a scheme between the optional from and to dates
+func (h *MutualFundHandler) GetNAVHistory(ctx iris.Context) {
+	code := ctx.Params().Get("code")
+
+	from, to, err := params.DateRange(ctx, mcservice.MarketLocation)
+	if err != nil {
+		ctx.StopWithJSON(iris.StatusBadRequest, mcmodels.FailedResponse{
+			Status:   iris.StatusBadRequest,
+			ErrorMsg: err.Error(),
+		})
+		return
+	}
+
+	navs, err := h.mutualFundService.GetNAVHistory(code, from, to)
+	if err != nil {
+		h.schemeFailed(ctx, code, err)
+		return
+	}
+	ctx.StatusCode(iris.StatusOK)
+	ctx.JSON(navs)
+}
+
+func (h *MutualFundHandler) schemeFailed(ctx iris.Context, code string, err error) {
+	if h.notConfigured(ctx, err) {
+		return
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		ctx.StopWithJSON(iris.StatusNotFound, mcmodels.FailedResponse{
+			Status:   iris.StatusNotFound,
+			ErrorMsg: "Scheme not found",
+		})
+		return
+	}
+	h.mlog.Error(fmt.Sprintf("Error handling scheme %s", code), err)
+	ctx.StopWithJSON(iris.StatusInternalServerError, mcmodels.FailedResponse{
+		Status:   iris.StatusInternalServerError,
+		ErrorMsg: "Something went wrong, please try again after some time",
+	})
+}
+
+// notConfigured answers with a 400 naming the missing setting when scraping isn't configured
+func (h *MutualFundHandler) notConfigured(ctx iris.Context, err error) bool {
+	if !errors.Is(err, service.ErrNotConfigured) {
+		return false
+	}
+	ctx.StopWithJSON(iris.StatusBadRequest, mcmodels.FailedResponse{
+		Status:   iris.StatusBadRequest,
+		ErrorMsg: err.Error(),
+	})
+	return true
+}
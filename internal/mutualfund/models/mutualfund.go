@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// Scheme is a mutual fund scheme listed on moneycontrol. Code is the moneycontrol scheme code and
+// Slug the scheme part of its NAV page URL.
+type Scheme struct {
+	ID         int64      `gorm:"primary_key NOT NULL AUTO_INCREMENT" json:"id"`
+	Code       string     `gorm:"uniqueIndex" json:"code"`
+	Name       string     `json:"name"`
+	Slug       string     `json:"slug"`
+	DelistedAt *time.Time `gorm:"index" json:"delisted_at,omitempty"`
+}
+
+// NAV is the net asset value of a scheme on a day, Date being the unix time of the day
+type NAV struct {
+	ID       int64   `gorm:"primary_key NOT NULL AUTO_INCREMENT" json:"-"`
+	SchemeID int64   `gorm:"uniqueIndex:idx_nav_scheme_date" json:"-"`
+	Date     int64   `gorm:"uniqueIndex:idx_nav_scheme_date" json:"date"`
+	Value    float64 `json:"nav"`
+}
+
+// SchemeFilter narrows a scheme listing. Query matches every whitespace separated word against
+// Name and Code.
+type SchemeFilter struct {
+	Query           string
+	IncludeDelisted bool
+	Page            int
+	PageSize        int
+}
+
+// SchemePage is one page of a scheme listing
+type SchemePage struct {
+	Total    int64    `json:"total"`
+	Page     int      `json:"page"`
+	PageSize int      `json:"page_size"`
+	Schemes  []Scheme `json:"schemes"`
+}
@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/johnsonabraham/moneycontrolscraper/internal/mutualfund/models"
+	"github.com/johnsonabraham/moneycontrolscraper/pkg/sqlutil"
+	"github.com/kataras/golog"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type MutualFundRepository interface {
+	RefreshSchemes(schemes []models.Scheme) (added, removed int, err error)
+	SearchSchemes(filter models.SchemeFilter) ([]models.Scheme, int64, error)
+	FetchScheme(code string) (*models.Scheme, error)
+	UpsertNAVs(schemeID int64, navs []models.NAV) error
+	FetchNAVs(schemeID int64, from, to int64) ([]models.NAV, error)
+}
+
+var errEmptySchemeRefresh = errors.New("refusing to refresh schemes from an empty crawl")
+
+type mutualFundRepository struct {
+	db   *gorm.DB
+	vlog *golog.Logger
+}
+
+func NewMutualFundRepository(db *gorm.DB, vlog *golog.Logger) *mutualFundRepository {
+	return &mutualFundRepository{
+		db:   db,
+		vlog: vlog,
+	}
+}
+
+// RefreshSchemes upserts the crawled schemes by code and marks stored schemes missing from the
+// crawl as delisted, returning how many schemes are new and how many got delisted
+func (s *mutualFundRepository) RefreshSchemes(schemes []models.Scheme) (added, removed int, err error) {
+	if len(schemes) == 0 {
+		// an empty crawl means moneycontrol failed us, not that every scheme closed
+		return 0, 0, errEmptySchemeRefresh
+	}
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		var existing []models.Scheme
+		if er := tx.Select("code", "delisted_at").Find(&existing).Error; er != nil {
+			return er
+		}
+		stored := make(map[string]bool, len(existing))
+		for _, scheme := range existing {
+			stored[scheme.Code] = true
+		}
+
+		crawled := make(map[string]bool, len(schemes))
+		var rows []models.Scheme
+		for _, scheme := range schemes {
+			if crawled[scheme.Code] {
+				continue
+			}
+			crawled[scheme.Code] = true
+			if !stored[scheme.Code] {
+				added++
+			}
+			rows = append(rows, scheme)
+		}
+		var missing []string
+		for _, scheme := range existing {
+			if !crawled[scheme.Code] && scheme.DelistedAt == nil {
+				missing = append(missing, scheme.Code)
+			}
+		}
+		removed = len(missing)
+
+		er := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "code"}},
+			DoUpdates: clause.AssignmentColumns([]string{"name", "slug", "delisted_at"}),
+		}).CreateInBatches(&rows, 500).Error
+		if er != nil {
+			return er
+		}
+		if len(missing) == 0 {
+			return nil
+		}
+		return tx.Model(&models.Scheme{}).Where("code IN ?", missing).Update("delisted_at", time.Now()).Error
+	})
+	if err != nil {
+		s.vlog.Error(err)
+		return 0, 0, err
+	}
+	return added, removed, nil
+}
+
+// SearchSchemes returns one page of the schemes matching filter ordered by name, with the total
+// number of matches
+func (s *mutualFundRepository) SearchSchemes(filter models.SchemeFilter) ([]models.Scheme, int64, error) {
+	query := s.db.Model(&models.Scheme{})
+	if !filter.IncludeDelisted {
+		query = query.Where("delisted_at IS NULL")
+	}
+	for _, word := range strings.Fields(filter.Query) {
+		pattern := "%" + sqlutil.EscapeLike(word) + "%"
+		query = query.Where("name ILIKE ? OR code ILIKE ?", pattern, pattern)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		s.vlog.Error(err)
+		return nil, 0, err
+	}
+	var schemes []models.Scheme
+	err := query.Order("name").Order("id").Offset((filter.Page - 1) * filter.PageSize).Limit(filter.PageSize).
+		Find(&schemes).Error
+	if err != nil {
+		s.vlog.Error(err)
+		return nil, 0, err
+	}
+	return schemes, total, nil
+}
+
+// FetchScheme looks a scheme up by its moneycontrol code
+func (s *mutualFundRepository) FetchScheme(code string) (*models.Scheme, error) {
+	var scheme models.Scheme
+	err := s.db.Where("upper(code) = ?", strings.ToUpper(code)).First(&scheme).Error
+	if err != nil {
+		return nil, err
+	}
+	return &scheme, nil
+}
+
+// UpsertNAVs stores the NAV history of a scheme, overwriting the value of days already stored
+func (s *mutualFundRepository) UpsertNAVs(schemeID int64, navs []models.NAV) error {
+	if len(navs) == 0 {
+		return nil
+	}
+	byDate := make(map[int64]int, len(navs))
+	var rows []models.NAV
+	for _, nav := range navs {
+		nav.ID = 0
+		nav.SchemeID = schemeID
+		if idx, found := byDate[nav.Date]; found {
+			rows[idx] = nav
+			continue
+		}
+		byDate[nav.Date] = len(rows)
+		rows = append(rows, nav)
+	}
+	err := s.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "scheme_id"}, {Name: "date"}},
+		DoUpdates: clause.AssignmentColumns([]string{"value"}),
+	}).CreateInBatches(&rows, 500).Error
+	if err != nil {
+		s.vlog.Error(err)
+		return err
+	}
+	return nil
+}
+
+// FetchNAVs returns the NAVs of a scheme between the from and to unix times in date order,
+// 0 leaving that end open
+func (s *mutualFundRepository) FetchNAVs(schemeID int64, from, to int64) ([]models.NAV, error) {
+	var navs []models.NAV
+	query := s.db.Where("scheme_id = ?", schemeID)
+	if from != 0 {
+		query = query.Where("date >= ?", from)
+	}
+	if to != 0 {
+		query = query.Where("date <= ?", to)
+	}
+	err := query.Order("date").Find(&navs).Error
+	if err != nil {
+		s.vlog.Error(err)
+		return nil, err
+	}
+	return navs, nil
+}
@@ -0,0 +1,250 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/johnsonabraham/moneycontrolscraper/config"
+	mcmodels "github.com/johnsonabraham/moneycontrolscraper/internal/moneycontrol/models"
+	mcservice "github.com/johnsonabraham/moneycontrolscraper/internal/moneycontrol/service"
+	"github.com/johnsonabraham/moneycontrolscraper/internal/mutualfund/models"
+	"github.com/johnsonabraham/moneycontrolscraper/internal/mutualfund/repository"
+	"github.com/kataras/golog"
+)
+
+const (
+	defaultSchemePageSize = 50
+	maxSchemePageSize     = 500
+)
+
+var (
+	// schemeLink matches the NAV page link of a scheme, capturing its slug and code
+	schemeLink = regexp.MustCompile(`/mutual-funds/nav/([^/]+)/([A-Za-z0-9]+)`)
+
+	// ErrNotConfigured is returned when the moneycontrol URL a scrape needs isn't set
+	ErrNotConfigured = errors.New("mutual fund scraping is not configured")
+)
+
+// navHistoryJson is the moneycontrol chart history response of a scheme, t holding the days and
+// c the NAV of each
+type navHistoryJson struct {
+	Status    string    `json:"s"`
+	Timestamp []int64   `json:"t"`
+	Close     []float64 `json:"c"`
+}
+
+type MutualFundService interface {
+	CaptureSchemes() error
+	EnqueueSchemeCollection() (*mcmodels.Job, error)
+	CaptureNAVHistory(code string) error
+	EnqueueNAVCollection(code string) (*mcmodels.Job, error)
+	SearchSchemes(filter models.SchemeFilter) (models.SchemePage, error)
+	GetNAVHistory(code string, from, to time.Time) ([]models.NAV, error)
+}
+
+type mutualFundService struct {
+	mlog                 *golog.Logger
+	cfg                  *config.AppEnvVars
+	mutualFundRepository repository.MutualFundRepository
+	jobs                 *mcservice.JobRunner
+}
+
+func NewMutualFundService(mlog *golog.Logger, cfg *config.AppEnvVars, mutualFundRepository repository.MutualFundRepository, jobs *mcservice.JobRunner) *mutualFundService {
+	return &mutualFundService{
+		mlog:                 mlog,
+		cfg:                  cfg,
+		mutualFundRepository: mutualFundRepository,
+		jobs:                 jobs,
+	}
+}
+
+// CaptureSchemes crawls the A to Z scheme lists, the letter being appended to
+// MONEYCONTROL_MF_SCHEMES_URL, and refreshes the scheme master
+func (i *mutualFundService) CaptureSchemes() error {
+	if i.cfg.MoneyControlMFSchemesURL == "" {
+		return fmt.Errorf("%w: MONEYCONTROL_MF_SCHEMES_URL is empty", ErrNotConfigured)
+	}
+	var schemes []models.Scheme
+	for char := 'A'; char <= 'Z'; char++ {
+		response, err := http.Get(i.cfg.MoneyControlMFSchemesURL + string(char))
+		if err != nil {
+			// a partial crawl would delist every scheme of the missing page
+			i.mlog.Error("Error in fetching scheme list ", err.Error())
+			return err
+		}
+		if response.StatusCode != http.StatusOK {
+			response.Body.Close()
+			err = fmt.Errorf("unexpected status %d fetching scheme list %c", response.StatusCode, char)
+			i.mlog.Error(err)
+			return err
+		}
+		doc, err := goquery.NewDocumentFromReader(response.Body)
+		response.Body.Close()
+		if err != nil {
+			i.mlog.Error("Error parsing scheme list ", err.Error())
+			return err
+		}
+		found := len(schemes)
+		doc.Find("a[href]").Each(func(_ int, s *goquery.Selection) {
+			link, _ := s.Attr("href")
+			match := schemeLink.FindStringSubmatch(link)
+			name := strings.Join(strings.Fields(s.Text()), " ")
+			if match == nil || name == "" {
+				return
+			}
+			schemes = append(schemes, models.Scheme{Code: match[2], Slug: match[1], Name: name})
+		})
+		if len(schemes) == found {
+			// an error page without schemes would delist every scheme of the letter just the same
+			err = fmt.Errorf("no schemes listed on scheme list %c", char)
+			i.mlog.Error(err)
+			return err
+		}
+	}
+	added, removed, err := i.mutualFundRepository.RefreshSchemes(schemes)
+	if err != nil {
+		i.mlog.Error("Error while saving schemes")
+		return err
+	}
+	i.mlog.Info(fmt.Sprintf("Captured %d schemes, %d added, %d removed", len(schemes), added, removed))
+	return nil
+}
+
+// EnqueueSchemeCollection queues a job refreshing the scheme master
+func (i *mutualFundService) EnqueueSchemeCollection() (*mcmodels.Job, error) {
+	if i.cfg.MoneyControlMFSchemesURL == "" {
+		return nil, fmt.Errorf("%w: MONEYCONTROL_MF_SCHEMES_URL is empty", ErrNotConfigured)
+	}
+	return i.jobs.Enqueue(mcmodels.JobCollectMFSchemes, "", func(progress *mcservice.JobProgress) error {
+		progress.SetTotal(1)
+		err := i.CaptureSchemes()
+		progress.Step(err)
+		return err
+	})
+}
+
+// CaptureNAVHistory fetches the daily NAV history of a scheme and stores it
+func (i *mutualFundService) CaptureNAVHistory(code string) error {
+	if i.cfg.MoneyControlMFNAVHistoryURL == "" {
+		return fmt.Errorf("%w: MONEYCONTROL_MF_NAV_HISTORY_URL is empty", ErrNotConfigured)
+	}
+	scheme, err := i.mutualFundRepository.FetchScheme(code)
+	if err != nil {
+		i.mlog.Error("Error fetching provided scheme", err)
+		return err
+	}
+	response, err := http.Get(fmt.Sprintf(i.cfg.MoneyControlMFNAVHistoryURL, scheme.Code, fmt.Sprint(time.Now().Unix())))
+	if err != nil {
+		i.mlog.Error(err)
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		err = fmt.Errorf("unexpected status %d fetching NAV history for %s", response.StatusCode, code)
+		i.mlog.Error(err)
+		return err
+	}
+
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		i.mlog.Error(fmt.Sprintf("Failed to read the NAV history of %s:", code), err)
+		return err
+	}
+	navs, err := parseNAVs(body)
+	if err != nil {
+		i.mlog.Error(fmt.Sprintf("Failed to parse the NAV history of %s:", code), err)
+		return err
+	}
+	if err := i.mutualFundRepository.UpsertNAVs(scheme.ID, navs); err != nil {
+		i.mlog.Error(fmt.Sprintf("Error saving NAV history for %s", code), err)
+		return err
+	}
+	return nil
+}
+
+// parseNAVs converts the column oriented chart history response into NAVs
+func parseNAVs(body []byte) ([]models.NAV, error) {
+	var history navHistoryJson
+	if err := json.Unmarshal(body, &history); err != nil {
+		return nil, err
+	}
+	if history.Status != "ok" {
+		// moneycontrol answers "no_data" for schemes without history
+		return nil, nil
+	}
+	if len(history.Close) != len(history.Timestamp) {
+		return nil, fmt.Errorf("NAV history arrays have mismatched lengths")
+	}
+	navs := make([]models.NAV, len(history.Timestamp))
+	for idx, ts := range history.Timestamp {
+		navs[idx] = models.NAV{Date: ts, Value: history.Close[idx]}
+	}
+	return navs, nil
+}
+
+// EnqueueNAVCollection queues a job capturing the NAV history of a scheme
+func (i *mutualFundService) EnqueueNAVCollection(code string) (*mcmodels.Job, error) {
+	if i.cfg.MoneyControlMFNAVHistoryURL == "" {
+		return nil, fmt.Errorf("%w: MONEYCONTROL_MF_NAV_HISTORY_URL is empty", ErrNotConfigured)
+	}
+	if _, err := i.mutualFundRepository.FetchScheme(code); err != nil {
+		return nil, err
+	}
+	return i.jobs.Enqueue(mcmodels.JobCollectMFNAVHistory, code, func(progress *mcservice.JobProgress) error {
+		progress.SetTotal(1)
+		err := i.CaptureNAVHistory(code)
+		progress.Step(err)
+		return err
+	})
+}
+
+// SearchSchemes returns one page of the scheme master
+func (i *mutualFundService) SearchSchemes(filter models.SchemeFilter) (models.SchemePage, error) {
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.PageSize < 1 {
+		filter.PageSize = defaultSchemePageSize
+	}
+	if filter.PageSize > maxSchemePageSize {
+		filter.PageSize = maxSchemePageSize
+	}
+	schemes, total, err := i.mutualFundRepository.SearchSchemes(filter)
+	if err != nil {
+		return models.SchemePage{}, err
+	}
+	if schemes == nil {
+		schemes = []models.Scheme{}
+	}
+	return models.SchemePage{
+		Total:    total,
+		Page:     filter.Page,
+		PageSize: filter.PageSize,
+		Schemes:  schemes,
+	}, nil
+}
+
+// GetNAVHistory returns the stored NAVs of a scheme between from and to, zero times leaving that
+// end open
+func (i *mutualFundService) GetNAVHistory(code string, from, to time.Time) ([]models.NAV, error) {
+	scheme, err := i.mutualFundRepository.FetchScheme(code)
+	if err != nil {
+		i.mlog.Error("Error fetching provided scheme", err)
+		return nil, err
+	}
+	var fromUnix, toUnix int64
+	if !from.IsZero() {
+		fromUnix = from.Unix()
+	}
+	if !to.IsZero() {
+		toUnix = to.Unix()
+	}
+	return i.mutualFundRepository.FetchNAVs(scheme.ID, fromUnix, toUnix)
+}
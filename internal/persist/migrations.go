@@ -0,0 +1,68 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// schemaMigration marks a one-off data migration as applied
+type schemaMigration struct {
+	Name      string `gorm:"primaryKey"`
+	AppliedAt time.Time
+}
+
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// migration is a data fix that runs once per database, its statements in one transaction
+type migration struct {
+	name       string
+	statements []string
+}
+
+var migrations = []migration{
+	{
+		// corporate action dates used to be read as UTC midnights rather than IST ones
+		name: "corporate_actions_ist_dates",
+		statements: []string{`UPDATE corporate_actions SET
+			announcement_date = CASE WHEN announcement_date > 0 AND announcement_date % 86400 = 0 THEN announcement_date - 19800 ELSE announcement_date END,
+			record_date = CASE WHEN record_date > 0 AND record_date % 86400 = 0 THEN record_date - 19800 ELSE record_date END,
+			ex_date = CASE WHEN ex_date > 0 AND ex_date % 86400 = 0 THEN ex_date - 19800 ELSE ex_date END`},
+	},
+	{
+		// dividend dates used to be read as UTC midnights rather than IST ones like corporate actions
+		name: "dividends_ist_dates",
+		statements: []string{`UPDATE dividends SET
+			announcement_date = CASE WHEN announcement_date > 0 AND announcement_date % 86400 = 0 THEN announcement_date - 19800 ELSE announcement_date END,
+			ex_date = CASE WHEN ex_date > 0 AND ex_date % 86400 = 0 THEN ex_date - 19800 ELSE ex_date END`},
+	},
+}
+
+// runMigrations applies the migrations not applied yet, in order
+func runMigrations(db *gorm.DB) {
+	for _, m := range migrations {
+		err := db.Transaction(func(tx *gorm.DB) error {
+			var applied schemaMigration
+			err := tx.Where("name = ?", m.name).First(&applied).Error
+			if err == nil {
+				return nil
+			}
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+			for _, statement := range m.statements {
+				if er := tx.Exec(statement).Error; er != nil {
+					return er
+				}
+			}
+			return tx.Create(&schemaMigration{Name: m.name, AppliedAt: time.Now()}).Error
+		})
+		if err != nil {
+			fmt.Println(fmt.Errorf("migration %s: %w", m.name, err))
+		}
+	}
+}
@@ -2,10 +2,12 @@ package db
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/johnsonabraham/moneycontrolscraper/config"
 	"github.com/johnsonabraham/moneycontrolscraper/internal/moneycontrol/models"
+	mfmodels "github.com/johnsonabraham/moneycontrolscraper/internal/mutualfund/models"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
@@ -29,7 +31,38 @@ func ConnectDB(cfg *config.AppEnvVars) *gorm.DB {
 	sqlDB.SetMaxOpenConns(5)
 	sqlDB.SetMaxIdleConns(5)
 	sqlDB.SetConnMaxLifetime(time.Minute * 10)
-	db.AutoMigrate(models.CompanyInfo{})
+	// more_data was an unused text column before it held the details payload, and
+	// empty strings don't cast to jsonb
+	if moreDataIsText(db) {
+		if err := db.Exec("UPDATE company_infos SET more_data = NULL WHERE more_data = ''").Error; err != nil {
+			fmt.Println(err)
+		}
+	}
+	db.AutoMigrate(models.CompanyInfo{}, models.Dividend{}, models.Candle{},
+		models.BackfillRun{}, models.BackfillProgress{}, models.Job{},
+		models.ScheduleRun{}, models.MarketDepth{}, models.FinancialLineItem{},
+		models.CorporateAction{}, models.Shareholding{}, models.Index{}, models.IndexConstituent{},
+		models.Announcement{},
+		mfmodels.Scheme{}, mfmodels.NAV{}, schemaMigration{})
+	runMigrations(db)
 
 	return db
 }
+
+// moreDataIsText reports whether company_infos.more_data hasn't been migrated to jsonb yet
+func moreDataIsText(db *gorm.DB) bool {
+	if !db.Migrator().HasTable(&models.CompanyInfo{}) {
+		return false
+	}
+	columns, err := db.Migrator().ColumnTypes(&models.CompanyInfo{})
+	if err != nil {
+		fmt.Println(err)
+		return false
+	}
+	for _, column := range columns {
+		if column.Name() == "more_data" {
+			return strings.EqualFold(column.DatabaseTypeName(), "text")
+		}
+	}
+	return false
+}
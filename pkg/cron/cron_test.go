@@ -0,0 +1,108 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		spec    string
+		wantErr bool
+	}{
+		{"* * * * *", false},
+		{"30 16 * * 1-5", false},
+		{"*/15 9-15 * * 1-5", false},
+		{"0 0 1,15 * 0", false},
+		{"0-30/10 * * * 7", false},
+		{"5/20 * * * *", false},
+		{"* * * *", true},
+		{"* * * * * *", true},
+		{"60 * * * *", true},
+		{"* 24 * * *", true},
+		{"* * 0 * *", true},
+		{"* * * 13 *", true},
+		{"* * * * 8", true},
+		{"5-1 * * * *", true},
+		{"*/0 * * * *", true},
+		{"a * * * *", true},
+		{"1-b * * * *", true},
+	}
+	for _, tt := range tests {
+		_, err := Parse(tt.spec)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Parse(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+		}
+	}
+}
+
+func TestMatches(t *testing.T) {
+	// 2026-10-19 is a Monday
+	monday := time.Date(2026, 10, 19, 16, 30, 0, 0, time.UTC)
+	tests := []struct {
+		spec string
+		at   time.Time
+		want bool
+	}{
+		{"* * * * *", monday, true},
+		{"30 16 * * 1-5", monday, true},
+		{"30 16 * * 1-5", monday.Add(time.Minute), false},
+		{"30 16 * * 1-5", monday.AddDate(0, 0, -1), false},
+		{"*/15 * * * *", monday, true},
+		{"*/15 * * * *", monday.Add(5 * time.Minute), false},
+		{"0-30/10 16 * * *", monday, true},
+		{"5/20 * * * *", monday.Add(-5 * time.Minute), true},
+		{"30 16 * * 7", monday.AddDate(0, 0, -1), true},
+		{"30 16 * * 0", monday.AddDate(0, 0, -1), true},
+		{"30 16 * 11 *", monday, false},
+		// both day fields restricted, either matching fires
+		{"30 16 1 * 1", monday, true},
+		{"30 16 19 * 0", monday, true},
+		{"30 16 1 * 0", monday, false},
+		// a day field starting with * isn't restricted, so both have to match
+		{"30 16 */2 * 1", monday, true},
+		{"30 16 */2 * 1", monday.AddDate(0, 0, 14), false},
+		{"30 16 */2 * 1", monday.AddDate(0, 0, 2), false},
+		{"30 16 1-31/2 * 1", monday, true},
+		{"30 16 19 * */2", monday, false},
+		{"30 16 19 * */2", monday.AddDate(0, 0, 1), false},
+	}
+	for _, tt := range tests {
+		schedule, err := Parse(tt.spec)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.spec, err)
+		}
+		if got := schedule.Matches(tt.at); got != tt.want {
+			t.Errorf("Parse(%q).Matches(%s) = %v, want %v", tt.spec, tt.at.Format(time.RFC1123), got, tt.want)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	// 2026-10-19 is a Monday
+	at := time.Date(2026, 10, 19, 16, 30, 20, 0, time.UTC)
+	tests := []struct {
+		spec string
+		from time.Time
+		want time.Time
+	}{
+		{"* * * * *", at, time.Date(2026, 10, 19, 16, 31, 0, 0, time.UTC)},
+		{"30 16 * * 1-5", at, time.Date(2026, 10, 20, 16, 30, 0, 0, time.UTC)},
+		{"30 16 * * 1-5", time.Date(2026, 10, 23, 17, 0, 0, 0, time.UTC), time.Date(2026, 10, 26, 16, 30, 0, 0, time.UTC)},
+		{"*/15 * * * *", at, time.Date(2026, 10, 19, 16, 45, 0, 0, time.UTC)},
+		{"0 0 1 * *", at, time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 1 *", at, time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 9 29 2 *", at, time.Date(2028, 2, 29, 9, 0, 0, 0, time.UTC)},
+		{"0 12 1 * 0", at, time.Date(2026, 10, 25, 12, 0, 0, 0, time.UTC)},
+		{"0 0 31 2 *", at, time.Time{}},
+	}
+	for _, tt := range tests {
+		schedule, err := Parse(tt.spec)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.spec, err)
+		}
+		if got := schedule.Next(tt.from); !got.Equal(tt.want) {
+			t.Errorf("Parse(%q).Next(%s) = %s, want %s", tt.spec, tt.from.Format(time.RFC1123), got, tt.want)
+		}
+	}
+}
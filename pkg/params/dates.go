@@ -0,0 +1,28 @@
+package params
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kataras/iris/v12"
+)
+
+// DateFormat is the layout of date query params
+const DateFormat = "2006-01-02"
+
+// DateRange reads the optional from and to date query params as days in loc, to covering the whole
+// of its day. A missing param is returned as the zero time.
+func DateRange(ctx iris.Context, loc *time.Location) (from, to time.Time, err error) {
+	if param := ctx.URLParam("from"); param != "" {
+		if from, err = time.ParseInLocation(DateFormat, param, loc); err != nil {
+			return from, to, fmt.Errorf("from must be a date in YYYY-MM-DD format")
+		}
+	}
+	if param := ctx.URLParam("to"); param != "" {
+		if to, err = time.ParseInLocation(DateFormat, param, loc); err != nil {
+			return from, to, fmt.Errorf("to must be a date in YYYY-MM-DD format")
+		}
+		to = to.Add(24*time.Hour - time.Second)
+	}
+	return from, to, nil
+}
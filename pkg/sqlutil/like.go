@@ -0,0 +1,8 @@
+package sqlutil
+
+import "strings"
+
+// EscapeLike escapes the LIKE wildcards of user input
+func EscapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}